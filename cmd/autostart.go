@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/autostart"
+	"github.com/yoshapihoff/smart-clipboard/internal/tray"
+)
+
+// runAutostart включает (on) или выключает (off) запуск при входе в
+// систему, без аргументов показывает текущее состояние
+func runAutostart(args []string) error {
+	if !autostart.Supported() {
+		return autostart.ErrUnsupported
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("usage: smart-clipboard autostart [on|off]")
+	}
+
+	action := ""
+	if len(args) == 1 {
+		action = args[0]
+	}
+
+	switch action {
+	case "":
+	case "on":
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		if err := autostart.Enable(exe, tray.Icon()); err != nil {
+			return err
+		}
+	case "off":
+		if err := autostart.Disable(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown autostart action %q, expected on or off", action)
+	}
+
+	if autostart.Enabled() {
+		fmt.Println("Start at login: on")
+	} else {
+		fmt.Println("Start at login: off")
+	}
+	return nil
+}
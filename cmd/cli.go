@@ -0,0 +1,568 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/prompt"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+	"github.com/yoshapihoff/smart-clipboard/internal/tui"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+const usage = `usage: smart-clipboard [--headless] [-debug] [-log-level LEVEL] [-set KEY=VALUE]...
+       smart-clipboard command [flags]
+
+Without a command the clipboard daemon with the tray icon is started.
+With --headless, or in a build without cgo, it runs without the tray
+until SIGINT or SIGTERM and is controlled by the commands below.
+Changes to config.yaml are applied while the daemon runs; SIGHUP and
+the reload command make it re-read the file at once.
+
+Settings come from config.yaml (see "config print"), environment
+variables named after the keys (SMART_CLIPBOARD_MAX_ITEMS,
+SMART_CLIPBOARD_WEB_LISTEN) and -set, each overriding the previous.
+
+Commands:
+  list [-n N] [-kind KIND]       show history
+  search [-n N] QUERY            show items containing QUERY
+  get REF                        print the full content of an item
+  copy REF                       put an item on the clipboard
+  pick [-print]                  choose an item in a full-screen terminal picker
+  menu [-select] [-with CMD]     print history for dmenu/rofi/fzf or copy the chosen line
+  add [-pin] [TEXT...]           add TEXT, or standard input, to history
+  rm REF...                      delete items
+  pin [-off] REF                 pin or unpin an item
+  clear                          delete all items
+  pause [DURATION]               pause capture (until resumed without DURATION)
+  resume                         resume capture
+  status                         show daemon state
+  reload                         re-read the configuration
+  unlock                         unlock history locked after inactivity
+  export [FILE]                  write history as JSON to FILE or standard output
+  import [-replace] [FILE]       read history from FILE or standard input
+  web [-open] [-token]           print (or open) the link to the web UI, or the API token
+  autostart [on|off]             show or change starting at login (XDG autostart)
+  install-service [-socket] [-headless] [-enable]
+                                 install a systemd user service
+  config print|validate|edit     show, check or edit the configuration
+  storage ...                    maintain the history file
+
+REF is a position from "list" or an item ID. Most commands accept -json.
+When the daemon is not running, history commands work on the file directly.`
+
+// exitCode завершает команду с кодом без сообщения об ошибке
+type exitCode int
+
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(c))
+}
+
+// parseArgs разбирает флаги в любом месте командной строки и возвращает
+// позиционные аргументы
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseRef разбирает ссылку на элемент: номер в списке или ID
+func parseRef(arg string) ipc.ItemRef {
+	if index, err := strconv.Atoi(arg); err == nil {
+		return ipc.ItemRef{Index: index}
+	}
+	return ipc.ItemRef{ID: arg}
+}
+
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func printItems(items []ipc.Item, asJSON bool) error {
+	if asJSON {
+		return printJSON(items)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tID\tKIND\tFLAGS\tAGE\tPREVIEW")
+	for _, item := range items {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			item.Index, item.ID, item.Kind, itemFlags(item), formatAge(time.Since(item.Timestamp)),
+			strings.Join(strings.Fields(item.Preview), " "))
+	}
+	return tw.Flush()
+}
+
+// itemFlags: P — закреплён, S — конфиденциальный
+func itemFlags(item ipc.Item) string {
+	flags := ""
+	if item.Pinned {
+		flags += "P"
+	}
+	if item.Sensitive {
+		flags += "S"
+	}
+	if flags == "" {
+		return "-"
+	}
+	return flags
+}
+
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+func printStatus(status ipc.Status, asJSON bool) error {
+	if asJSON {
+		return printJSON(status)
+	}
+
+	fmt.Printf("Running:  yes (pid %d)\n", status.PID)
+	switch {
+	case !status.Paused:
+		fmt.Println("Capture:  active")
+	case status.PausedUntil != nil:
+		fmt.Printf("Capture:  paused until %s\n", status.PausedUntil.Local().Format("15:04:05"))
+	default:
+		fmt.Println("Capture:  paused until resumed")
+	}
+	if status.Locked {
+		fmt.Println("History:  locked")
+	} else {
+		fmt.Printf("History:  %d items\n", status.Items)
+	}
+	if status.Sync {
+		fmt.Printf("Sync:     on, %d peers\n", status.Peers)
+	} else {
+		fmt.Println("Sync:     off")
+	}
+	return nil
+}
+
+func runList(args []string, search bool) error {
+	name := "list"
+	if search {
+		name = "search"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	limit := fs.Int("n", 0, "show at most N items")
+	kind := fs.String("kind", "", "show only items of this kind (text, url, secret)")
+	asJSON := fs.Bool("json", false, "print JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	params := ipc.ListParams{Kind: *kind, Limit: *limit}
+	method := ipc.MethodList
+	if search {
+		if len(rest) == 0 {
+			return fmt.Errorf("usage: smart-clipboard search [-n N] QUERY")
+		}
+		params.Query = strings.Join(rest, " ")
+		method = ipc.MethodSearch
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var items []ipc.Item
+	if err := conn.Call(method, params, &items); err != nil {
+		return err
+	}
+	return printItems(items, *asJSON)
+}
+
+func runGet(args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: smart-clipboard get REF")
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var item ipc.Item
+	if err := conn.Call(ipc.MethodGet, parseRef(rest[0]), &item); err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(item)
+	}
+	_, err = io.WriteString(os.Stdout, item.Content)
+	return err
+}
+
+func runCopy(args []string) error {
+	fs := flag.NewFlagSet("copy", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: smart-clipboard copy REF")
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var item ipc.Item
+	if err := conn.Call(ipc.MethodCopy, parseRef(rest[0]), &item); err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(item)
+	}
+	return nil
+}
+
+func runAdd(args []string) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	pin := fs.Bool("pin", false, "pin the new item")
+	asJSON := fs.Bool("json", false, "print JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	if len(rest) == 0 || (len(rest) == 1 && rest[0] == "-") {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		content = string(data)
+	} else {
+		content = strings.Join(rest, " ")
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var item ipc.Item
+	if err := conn.Call(ipc.MethodAdd, ipc.AddParams{Content: content, Pinned: *pin}, &item); err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(item)
+	}
+	fmt.Println(item.ID)
+	return nil
+}
+
+func runRemove(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: smart-clipboard rm REF...")
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Номера сдвигаются после удаления, поэтому сначала переводим их в ID
+	var ids []string
+	for _, arg := range args {
+		var item ipc.Item
+		if err := conn.Call(ipc.MethodGet, parseRef(arg), &item); err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		ids = append(ids, item.ID)
+	}
+	for _, id := range ids {
+		if err := conn.Call(ipc.MethodDelete, ipc.ItemRef{ID: id}, nil); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func runPin(args []string) error {
+	fs := flag.NewFlagSet("pin", flag.ContinueOnError)
+	off := fs.Bool("off", false, "unpin the item")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: smart-clipboard pin [-off] REF")
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	params := ipc.PinParams{ItemRef: parseRef(rest[0]), Pinned: !*off}
+	return conn.Call(ipc.MethodPin, params, nil)
+}
+
+func runClear() error {
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Call(ipc.MethodClear, nil, nil)
+}
+
+// runPause приостанавливает (или с resume возобновляет) захват; нужен работающий демон
+func runPause(args []string, resume bool) error {
+	name := "pause"
+	if resume {
+		name = "resume"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	conn, err := connectDaemon()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var status ipc.Status
+	if resume {
+		err = conn.Call(ipc.MethodResume, nil, &status)
+	} else {
+		var params ipc.PauseParams
+		if len(rest) > 0 {
+			params.Duration = rest[0]
+		}
+		err = conn.Call(ipc.MethodPause, params, &status)
+	}
+	if err != nil {
+		return err
+	}
+	return printStatus(status, *asJSON)
+}
+
+// runReload просит демон перечитать конфигурацию. Обычно это не нужно:
+// демон сам замечает изменения файла.
+func runReload(args []string) error {
+	fs := flag.NewFlagSet("reload", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	conn, err := connectDaemon()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var status ipc.Status
+	if err := conn.Call(ipc.MethodReload, nil, &status); err != nil {
+		return err
+	}
+	return printStatus(status, *asJSON)
+}
+
+// runUnlock снимает блокировку истории работающего демона. Пароль берётся
+// из SMART_CLIPBOARD_PASSPHRASE или запрашивается в терминале.
+func runUnlock(args []string) error {
+	fs := flag.NewFlagSet("unlock", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	conn, err := connectDaemon()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var status ipc.Status
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		err = conn.Call(ipc.MethodUnlock, ipc.UnlockParams{Passphrase: passphrase}, &status)
+	} else {
+		for attempt := 1; ; attempt++ {
+			var passphrase []byte
+			passphrase, err = prompt.Passphrase("Smart-clipboard passphrase")
+			if err != nil {
+				return err
+			}
+			err = conn.Call(ipc.MethodUnlock, ipc.UnlockParams{Passphrase: string(passphrase)}, &status)
+			var rpcErr *ipc.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != ipc.CodeWrongKey || attempt == maxUnlockAttempts {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return printStatus(status, *asJSON)
+}
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	conn, err := connectDaemon()
+	if err != nil {
+		if *asJSON {
+			printJSON(map[string]bool{"running": false})
+		} else {
+			fmt.Println("Running:  no")
+		}
+		return exitCode(3)
+	}
+	defer conn.Close()
+
+	var status ipc.Status
+	if err := conn.Call(ipc.MethodStatus, nil, &status); err != nil {
+		return err
+	}
+	return printStatus(status, *asJSON)
+}
+
+func runExport(args []string) error {
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var items []types.ClipboardItem
+	if err := conn.Call(ipc.MethodExport, nil, &items); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	// Файл заменяется целиком только после успешной выгрузки: при ошибке
+	// прежний экспорт остаётся нетронутым
+	if len(args) > 0 && args[0] != "-" {
+		return storage.WriteFileAtomic(args[0], data, 0600)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	replace := fs.Bool("replace", false, "replace history instead of merging")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	in := os.Stdin
+	if len(rest) > 0 && rest[0] != "-" {
+		file, err := os.Open(rest[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var items []types.ClipboardItem
+	if err := json.NewDecoder(in).Decode(&items); err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var imported int
+	if err := conn.Call(ipc.MethodImport, ipc.ImportParams{Items: items, Replace: *replace}, &imported); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d items.\n", imported)
+	return nil
+}
+
+// runPick показывает интерактивный выбор элемента в терминале
+func runPick(args []string) error {
+	fs := flag.NewFlagSet("pick", flag.ContinueOnError)
+	print := fs.Bool("print", false, "print the chosen item instead of copying it")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	result, err := tui.Run(conn, tui.Options{Print: *print})
+	if err != nil {
+		return err
+	}
+	if !result.Selected {
+		return exitCode(1)
+	}
+	if *print {
+		_, err = io.WriteString(os.Stdout, result.Content)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// offlineEnv направляет команды к истории во временном каталоге: демона
+// там нет, и они открывают историю сами
+func offlineEnv(t *testing.T, config string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	configPath := filepath.Join(dir, "config.yaml")
+	data := "storage_path: " + filepath.Join(dir, "history.json") + "\nlogging:\n  file: \"off\"\n" + config
+	if err := os.WriteFile(configPath, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SMART_CLIPBOARD_CONFIG", configPath)
+	return dir
+}
+
+func TestExportKeepsFileOnError(t *testing.T) {
+	dir := offlineEnv(t, "max_items: broken\n")
+	out := filepath.Join(dir, "export.json")
+	if err := os.WriteFile(out, []byte("previous export"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runExport([]string{out}); err == nil {
+		t.Fatal("export with a broken config succeeded")
+	}
+	if data, _ := os.ReadFile(out); string(data) != "previous export" {
+		t.Fatalf("export file = %q, want it untouched", data)
+	}
+}
+
+func TestExportWritesFile(t *testing.T) {
+	dir := offlineEnv(t, "")
+	out := filepath.Join(dir, "export.json")
+	if err := os.WriteFile(out, []byte("previous export"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runExport([]string{out}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "[]\n" {
+		t.Fatalf("export file = %q, want an empty list", data)
+	}
+	if temps, _ := filepath.Glob(filepath.Join(dir, ".export.json.tmp-*")); len(temps) != 0 {
+		t.Fatalf("temporary files left behind: %v", temps)
+	}
+}
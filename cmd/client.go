@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// errNotRunning возвращается командами, которым нужен работающий демон
+var errNotRunning = errors.New("smart-clipboard is not running")
+
+// caller вызывает методы API демона (см. пакет ipc)
+type caller interface {
+	Call(method string, params, result any) error
+}
+
+// connection — подключение к демону или, если он не запущен,
+// к файлу истории напрямую
+type connection struct {
+	caller
+	offline bool
+	close   func()
+}
+
+func (c *connection) Close() {
+	if c.close != nil {
+		c.close()
+	}
+}
+
+// connectDaemon подключается только к работающему демону
+func connectDaemon() (*connection, error) {
+	client, err := ipc.Dial()
+	if err != nil {
+		return nil, errNotRunning
+	}
+	return &connection{caller: client, close: func() { client.Close() }}, nil
+}
+
+// connect подключается к демону, а если он не запущен — открывает историю
+// в текущем процессе и выполняет методы тем же кодом, что и демон
+func connect() (*connection, error) {
+	if conn, err := connectDaemon(); err == nil {
+		return conn, nil
+	}
+
+	cfg, store, inst, err := openStorage()
+	if err != nil {
+		return nil, err
+	}
+	if err := unlockStorage(store, cfg); err != nil {
+		inst.Release()
+		return nil, err
+	}
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		inst.Release()
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	manager := clipboard.NewManager(history, cfg.MaxItems, nil)
+	configureManager(manager, cfg, store)
+	store.SetHistoryCallback(manager.GetHistory)
+	manager.SetChangeCallback(func(change types.Change) {
+		if err := store.Append(change); err != nil {
+			logger.Error("failed to save history", "err", err)
+		}
+	})
+
+	return &connection{
+		caller:  ipc.NewServer(manager, nil),
+		offline: true,
+		close: func() {
+			closeOffline(store)
+			inst.Release()
+		},
+	}, nil
+}
+
+// closeOffline записывает накопленный журнал в снимок, раз демона,
+// который сделал бы это позже, нет
+func closeOffline(store *storage.Storage) {
+	if err := store.Compact(); err != nil {
+		logger.Warn("journal compaction failed", "err", err)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/instance"
+	"github.com/yoshapihoff/smart-clipboard/internal/prompt"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+)
+
+// storageLockWait — сколько команда ждёт освобождения истории другой командой
+const storageLockWait = 5 * time.Second
+
+// runCommand выполняет подкоманду и возвращает код завершения процесса
+func runCommand(args []string) int {
+	var err error
+
+	switch args[0] {
+	case "list":
+		err = runList(args[1:], false)
+	case "search":
+		err = runList(args[1:], true)
+	case "get":
+		err = runGet(args[1:])
+	case "copy":
+		err = runCopy(args[1:])
+	case "pick":
+		err = runPick(args[1:])
+	case "menu":
+		err = runMenu(args[1:])
+	case "add":
+		err = runAdd(args[1:])
+	case "rm":
+		err = runRemove(args[1:])
+	case "pin":
+		err = runPin(args[1:])
+	case "clear":
+		err = runClear()
+	case "pause":
+		err = runPause(args[1:], false)
+	case "resume":
+		err = runPause(args[1:], true)
+	case "status":
+		err = runStatus(args[1:])
+	case "reload":
+		err = runReload(args[1:])
+	case "unlock":
+		err = runUnlock(args[1:])
+	case "export":
+		err = runExport(args[1:])
+	case "import":
+		err = runImport(args[1:])
+	case "web":
+		err = runWeb(args[1:])
+	case "autostart":
+		err = runAutostart(args[1:])
+	case "install-service":
+		err = runInstallService(args[1:])
+	case "config":
+		err = runConfigCommand(args[1:])
+	case "storage":
+		err = runStorageCommand(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Println(usage)
+	default:
+		err = fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return 2
+	}
+	var code exitCode
+	if errors.As(err, &code) {
+		return int(code)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "smart-clipboard: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// isHelpFlag сообщает, что аргумент — запрос справки, а не флаг демона
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func runStorageCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: smart-clipboard storage rekey [-key-file path] | verify [-repair] | gc")
+	}
+
+	switch args[0] {
+	case "rekey":
+		return runStorageRekey(args[1:])
+	case "verify":
+		return runStorageVerify(args[1:])
+	case "gc":
+		return runStorageGC()
+	default:
+		return fmt.Errorf("unknown storage command %q", args[0])
+	}
+}
+
+// runStorageRekey перешифровывает историю новым паролем или файлом ключа.
+// Для файла в открытом виде это включает шифрование.
+func runStorageRekey(args []string) error {
+	fs := flag.NewFlagSet("storage rekey", flag.ContinueOnError)
+	keyFile := fs.String("key-file", "", "encrypt with the key from this file instead of a passphrase")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, store, inst, err := openStorage()
+	if err != nil {
+		return err
+	}
+	defer inst.Release()
+
+	encrypted, err := store.IsEncrypted()
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		if err := unlockStorage(store, cfg); err != nil {
+			return err
+		}
+	}
+
+	newSecret := storage.Secret{KeyFile: *keyFile}
+	if *keyFile == "" {
+		newSecret.Passphrase, err = prompt.NewPassphrase("New smart-clipboard passphrase")
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := store.Rekey(newSecret); err != nil {
+		return err
+	}
+
+	fmt.Println("History re-encrypted. Update encryption/encryption_key_file in config.yaml if needed.")
+	return nil
+}
+
+// runStorageVerify проверяет файл истории и резервные копии, а с -repair
+// восстанавливает повреждённый файл из резервной копии
+func runStorageVerify(args []string) error {
+	fs := flag.NewFlagSet("storage verify", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "restore a damaged history file from the newest valid backup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, store, inst, err := openStorage()
+	if err != nil {
+		return err
+	}
+	defer inst.Release()
+	if err := unlockExisting(store, cfg); err != nil {
+		return err
+	}
+
+	report := store.Verify()
+	fmt.Println(report.Primary)
+	for _, backup := range report.Backups {
+		fmt.Println(backup)
+	}
+	for _, tmp := range report.TempFiles {
+		fmt.Printf("%s: leftover from an interrupted write\n", tmp)
+	}
+
+	if report.Healthy() {
+		return nil
+	}
+	if !*repair {
+		return fmt.Errorf("history storage is damaged, run with -repair to fix it")
+	}
+	if err := store.Repair(); err != nil {
+		return err
+	}
+	fmt.Println("Repaired.")
+	return nil
+}
+
+// runStorageGC удаляет блобы, на которые больше нет ссылок
+func runStorageGC() error {
+	cfg, store, inst, err := openStorage()
+	if err != nil {
+		return err
+	}
+	defer inst.Release()
+	if err := unlockExisting(store, cfg); err != nil {
+		return err
+	}
+
+	removed, err := store.CollectGarbage()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d unreferenced blobs.\n", removed)
+	return nil
+}
+
+// openStorage загружает конфигурацию и открывает хранилище истории.
+// Работающий экземпляр в это время не должен писать в ту же историю,
+// поэтому блокировка экземпляра удерживается до завершения команды.
+// Другую команду, например соседнюю в конвейере, недолго ждём.
+// Вызывающий освобождает блокировку через Release.
+func openStorage() (*config.Config, *storage.Storage, *instance.Instance, error) {
+	inst, err := instance.AcquireWait(storageLockWait)
+	if errors.Is(err, instance.ErrRunning) {
+		return nil, nil, nil, fmt.Errorf("history is in use by another smart-clipboard process; stop the daemon before running storage commands")
+	} else if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cfg, store, err := loadStorage()
+	if err != nil {
+		inst.Release()
+		return nil, nil, nil, err
+	}
+	return cfg, store, inst, nil
+}
+
+func loadStorage() (*config.Config, *storage.Storage, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := storage.NewStorage(cfg.StoragePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	store.SetBackupCount(cfg.BackupCount)
+	if err := store.SetEngine(cfg.StorageEngine); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, store, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+)
+
+const configUsage = "usage: smart-clipboard config print [-defaults] | validate [FILE] | edit"
+
+func runConfigCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(configUsage)
+	}
+
+	switch args[0] {
+	case "print":
+		return runConfigPrint(args[1:])
+	case "validate":
+		return runConfigValidate(args[1:])
+	case "edit":
+		return runConfigEdit(args[1:])
+	default:
+		return fmt.Errorf("unknown config command %q\n%s", args[0], configUsage)
+	}
+}
+
+// runConfigPrint выводит действующую конфигурацию: файл вместе со значениями
+// по умолчанию и переменными окружения SMART_CLIPBOARD_*
+func runConfigPrint(args []string) error {
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	defaults := fs.Bool("defaults", false, "print the built-in defaults instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := config.DefaultConfig()
+	if !*defaults {
+		var err error
+		if cfg, err = config.LoadConfig(); err != nil {
+			return err
+		}
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(data)
+	return nil
+}
+
+// runConfigValidate проверяет файл конфигурации и перечисляет все ошибки
+func runConfigValidate(args []string) error {
+	if len(args) > 1 {
+		return errors.New("usage: smart-clipboard config validate [FILE]")
+	}
+
+	path := config.Path()
+	if len(args) == 1 {
+		path = args[0]
+	}
+	if _, err := config.LoadFile(path); err != nil {
+		return err
+	}
+	fmt.Printf("%s: OK\n", path)
+	return nil
+}
+
+// runConfigEdit открывает файл конфигурации в редакторе ($VISUAL или
+// $EDITOR) и проверяет его после сохранения. Отсутствующий файл
+// создаётся со значениями по умолчанию.
+func runConfigEdit(args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: smart-clipboard config edit")
+	}
+
+	path := config.Path()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := config.SaveConfig(config.DefaultConfig()); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if err := openEditor(path); err != nil {
+			return err
+		}
+
+		_, err := config.LoadFile(path)
+		if err == nil {
+			fmt.Printf("%s: OK\n", path)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		if !term.IsTerminal(int(os.Stdin.Fd())) || !askYesNo("Edit again?") {
+			return exitCode(1)
+		}
+	}
+}
+
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// Редактор может быть задан с аргументами, например "code -w"
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s: %w", fields[0], err)
+	}
+	return nil
+}
+
+// askYesNo задаёт вопрос на терминале; ответ по умолчанию — да
+func askYesNo(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [Y/n] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+)
+
+// daemonOptions — флаги запуска демона
+type daemonOptions struct {
+	headless bool
+}
+
+// parseDaemonFlags разбирает флаги командной строки демона
+func parseDaemonFlags(args []string) (daemonOptions, error) {
+	var opts daemonOptions
+
+	fs := flag.NewFlagSet("smart-clipboard", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // Ошибку вместе со справкой выводит main
+	fs.BoolVar(&opts.headless, "headless", false, "run without the tray icon, for servers and containers")
+	// Остальные флаги переопределяют настройки из файла и окружения
+	fs.Func("set", "override a config key, for example -set max_items=100", func(value string) error {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("expected KEY=VALUE, got %q", value)
+		}
+		return config.Override(key, val)
+	})
+	fs.BoolFunc("debug", "enable debug logging", func(value string) error {
+		return config.Override("debug_mode", value)
+	})
+	fs.Func("log-level", "default log level: debug, info, warn or error", func(value string) error {
+		return config.Override("logging.level", value)
+	})
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	return opts, nil
+}
+
+// shutdownTimeout ограничивает время остановки подсистем при завершении
+const shutdownTimeout = 10 * time.Second
+
+// handleSignals ждёт сигналов: SIGHUP перечитывает конфигурацию, SIGINT и
+// SIGTERM завершают работу. Возвращается при завершении или отмене ctx.
+func handleSignals(ctx context.Context, reload func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				reload()
+				continue
+			}
+			logger.Info("shutting down", "signal", sig)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// setupLogging направляет журнал демона в файл и настраивает уровни
+// из конфигурации
+func setupLogging(cfg *config.Config) error {
+	file := cfg.Logging.File
+	switch file {
+	case "off":
+		file = ""
+	case "":
+		var err error
+		if file, err = logging.DefaultFile(); err != nil {
+			return err
+		}
+	}
+
+	return logging.Setup(logging.Config{
+		Level:    cfg.Logging.Level,
+		Levels:   cfg.Logging.Levels,
+		Debug:    cfg.DebugMode,
+		File:     file,
+		MaxSize:  cfg.Logging.MaxSize,
+		MaxFiles: cfg.Logging.MaxFiles,
+	})
+}
+
+// fatal записывает ошибку в журнал и завершает процесс
+func fatal(msg string, err error) {
+	logger.Error(msg, "err", err)
+	logging.Close()
+	os.Exit(1)
+}
+
+// stopHook приводит функцию остановки без ошибки к виду хука lifecycle
+func stopHook(stop func()) func(context.Context) error {
+	return func(context.Context) error {
+		stop()
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/dbusservice"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/sync"
+)
+
+// pickerCommands — меню, которые ищутся, если dbus.picker_command не задан
+var pickerCommands = []string{
+	"rofi -dmenu -i -p clipboard",
+	"wofi --dmenu",
+	"fuzzel --dmenu",
+	"bemenu -i",
+	"dmenu -i -l 20",
+}
+
+// startDBusService публикует демон на сессионной шине D-Bus
+func startDBusService(api *ipc.Server, syncManager *sync.SyncManager, live *config.Live) *dbusservice.Service {
+	service, err := dbusservice.Export(api, func() error {
+		return showPicker(live.Load().DBus.PickerCommand)
+	})
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil
+	} else if err != nil {
+		logger.Warn("D-Bus service is not available", "err", err)
+		return nil
+	}
+
+	if syncManager != nil {
+		syncManager.SetPeerCallback(service.PeerChanged)
+	}
+	return service
+}
+
+// showPicker запускает "smart-clipboard menu -with <меню>" отдельным
+// процессом и не ждёт выбора
+func showPicker(command string) error {
+	if command == "" {
+		for _, candidate := range pickerCommands {
+			if _, err := exec.LookPath(strings.Fields(candidate)[0]); err == nil {
+				command = candidate
+				break
+			}
+		}
+	}
+	if command == "" {
+		return fmt.Errorf("no menu program found, set dbus.picker_command in config.yaml")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, "menu", "-with", command)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
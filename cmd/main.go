@@ -1,95 +1,338 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
 	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/instance"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/lifecycle"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+	"github.com/yoshapihoff/smart-clipboard/internal/maintenance"
+	"github.com/yoshapihoff/smart-clipboard/internal/session"
 	"github.com/yoshapihoff/smart-clipboard/internal/storage"
 	"github.com/yoshapihoff/smart-clipboard/internal/sync"
 	"github.com/yoshapihoff/smart-clipboard/internal/tray"
 	"github.com/yoshapihoff/smart-clipboard/internal/types"
 )
 
+var logger = logging.For("main")
+
 func main() {
-	// Загружаем базовую конфигурацию
-	cfg, err := config.LoadConfig()
+	// Флаги относятся к демону, всё остальное — подкоманды
+	args := os.Args[1:]
+	if len(args) > 0 && (!strings.HasPrefix(args[0], "-") || isHelpFlag(args[0])) {
+		os.Exit(runCommand(args))
+	}
+	opts, err := parseDaemonFlags(args)
 	if err != nil {
-		log.Printf("Ошибка загрузки конфигурации: %v", err)
+		fmt.Fprintf(os.Stderr, "smart-clipboard: %v\n%s\n", err, usage)
+		os.Exit(2)
+	}
+
+	// Второй демон не запускаем: управлять работающим можно командами
+	inst, instErr := instance.Acquire()
+	if errors.Is(instErr, instance.ErrRunning) {
+		fmt.Fprintln(os.Stderr, "smart-clipboard is already running, use \"smart-clipboard status\" to inspect it")
+		os.Exit(0)
+	}
+
+	// Загружаем базовую конфигурацию
+	cfg, cfgErr := config.LoadConfig()
+	if cfgErr != nil {
 		cfg = config.DefaultConfig()
 	}
 
+	// Журнал настраивается по конфигурации, поэтому ошибки выше
+	// записываются только теперь
+	if err := setupLogging(cfg); err != nil {
+		logger.Error("failed to set up logging", "err", err)
+	}
+	if instErr != nil {
+		logger.Warn("failed to check for a running instance", "err", instErr)
+	}
+	if cfgErr != nil {
+		logger.Error("failed to load config, using defaults", "err", cfgErr)
+		notify("Invalid configuration, using defaults: " + cfgErr.Error())
+	}
+
+	// Подсистемы останавливаются в порядке, обратном запуску
+	lc := lifecycle.New()
+	if inst != nil {
+		lc.OnStop("instance lock", stopHook(inst.Release))
+	}
+
 	// Теперь создаем хранилище и загружаем локальные данные
 	store, err := storage.NewStorage(cfg.StoragePath)
 	if err != nil {
-		log.Fatalf("Ошибка инициализации хранилища: %v", err)
+		fatal("failed to open storage", err)
+	}
+	store.SetBackupCount(cfg.BackupCount)
+	if err := store.SetEngine(cfg.StorageEngine); err != nil {
+		logger.Error("failed to select storage engine", "err", err)
+	}
+
+	// Разблокируем зашифрованную историю до первого чтения
+	if err := unlockStorage(store, cfg); err != nil {
+		fatal("failed to unlock storage", err)
 	}
 
 	// Загружаем локальную историю
 	localHistory, err := store.LoadHistory()
-	if err != nil {
-		log.Printf("Ошибка загрузки локальной истории: %v", err)
+	if errors.Is(err, storage.ErrNewerSchema) {
+		// Не перезаписываем историю, созданную более новой версией
+		fatal("failed to load history", err)
+	} else if err != nil {
+		logger.Error("failed to load history", "err", err)
 	}
 
 	// Настраиваем синхронизацию
 	historyChan := make(chan []types.ClipboardItem, 10)
 	syncManager, err := sync.NewSyncManager(historyChan)
 	if err != nil {
-		log.Printf("Ошибка инициализации синхронизации: %v", err)
+		logger.Error("failed to start sync", "err", err)
+	} else {
+		syncManager.SetEnabled(cfg.Sync.Enabled)
+		lc.OnStop("sync", stopHook(syncManager.Stop))
 	}
 
 	// Создаем менеджер буфера обмена с финальной историей
 	clipboardManager := clipboard.NewManager(localHistory, cfg.MaxItems, syncManager)
+	configureManager(clipboardManager, cfg, store)
+
+	// Автоблокировка возможна только при шифровании с паролем
+	if store.SupportsLock() {
+		clipboardManager.SetIdleLock(cfg.IdleLockTimeout)
+	}
 
 	// Устанавливаем callback для получения текущей истории
-	syncManager.SetHistoryCallback(func() []types.ClipboardItem {
-		return clipboardManager.GetHistory()
+	if syncManager != nil {
+		syncManager.SetHistoryCallback(func() []types.ClipboardItem {
+			return clipboardManager.SyncableHistory()
+		})
+	}
+
+	// Управляющий сокет для командной строки и скриптов
+	ipcServer := ipc.NewServer(clipboardManager, syncManager)
+
+	// Изменения конфигурации применяются на ходу через метод reload API
+	live := config.NewLive(cfg)
+	reloader := newReloader(live, clipboardManager, store, syncManager)
+	ipcServer.SetReloadHandler(reloader.reload)
+	if store.SupportsLock() {
+		// Без значка в трее историю разблокируют командой unlock
+		ipcServer.SetUnlockHandler(store.CheckPassphrase)
+	}
+	reload := func() {
+		ipcServer.Call(ipc.MethodReload, nil, nil)
+	}
+	lc.Go(func(ctx context.Context) {
+		config.Watch(ctx, reload)
+	})
+
+	// Сохраняем только изменения истории, без периодической перезаписи файла
+	store.SetHistoryCallback(clipboardManager.GetHistory)
+	clipboardManager.SetChangeCallback(func(change types.Change) {
+		if err := store.Append(change); err != nil {
+			logger.Error("failed to save history", "err", err)
+		}
+		ipcServer.Publish(change)
+	})
+	lc.OnStop("storage", func(context.Context) error {
+		return store.SaveHistory(clipboardManager.GetHistory())
 	})
 
-	go handleSyncMessages(clipboardManager, store, historyChan)
+	// Реагируем на блокировку экрана, если это задано в конфигурации
+	if len(cfg.ScreenLockActions) > 0 {
+		if watcher := startSessionWatcher(clipboardManager, syncManager, cfg.ScreenLockActions); watcher != nil {
+			lc.OnStop("session watcher", func(context.Context) error { return watcher.Close() })
+		}
+	}
+
+	lc.Go(func(ctx context.Context) {
+		handleSyncMessages(ctx, clipboardManager, historyChan)
+	})
 
-	go monitorClipboard(clipboardManager, store, cfg.CheckInterval)
-	tray.RunTray(clipboardManager, store, cfg)
+	lc.OnStop("maintenance", stopHook(startMaintenance(clipboardManager, store).Stop))
+
+	// Без блокировки экземпляра сокет может принадлежать другому демону
+	if inst != nil {
+		if err := startControlSocket(ipcServer); err != nil {
+			logger.Error("failed to start control socket", "err", err)
+		} else {
+			lc.OnStop("control socket", stopHook(ipcServer.Stop))
+		}
+		if cfg.Web.Enabled {
+			if server := startWebServer(ipcServer, cfg); server != nil {
+				lc.OnStop("web UI", server.Stop)
+			}
+		}
+		if cfg.DBus.Enabled {
+			if service := startDBusService(ipcServer, syncManager, live); service != nil {
+				lc.OnStop("D-Bus service", func(context.Context) error { return service.Close() })
+			}
+		}
+	}
+
+	lc.Go(func(ctx context.Context) {
+		monitorClipboard(ctx, clipboardManager, cfg.CheckInterval, reloader.intervals)
+	})
+
+	lc.Go(func(ctx context.Context) {
+		notifySystemd(ctx, ipcServer)
+	})
+
+	// Без cgo значка в трее нет, такая сборка всегда работает в фоне
+	if opts.headless || !tray.Available {
+		logger.Info("running without the tray icon, use smart-clipboard commands to control the daemon")
+		handleSignals(lc.Context(), reload)
+	} else {
+		// Сигнал завершения закрывает трей, пункт Quit — тоже
+		go func() {
+			handleSignals(lc.Context(), reload)
+			tray.Quit()
+		}()
+		tray.RunTray(clipboardManager, store, live)
+	}
+
+	lc.Shutdown(shutdownTimeout)
+	logging.Close()
 }
 
-func monitorClipboard(manager *clipboard.Manager, store *storage.Storage, interval time.Duration) {
+// configureManager применяет к менеджеру настройки хранения и конфиденциальности
+func configureManager(manager *clipboard.Manager, cfg *config.Config, store *storage.Storage) {
+	manager.SetBlobStore(store.Blobs(), cfg.BlobThreshold)
+
+	sensitivePolicy, err := clipboard.NewSensitivePolicy(cfg.SensitivePatterns, cfg.SensitiveKinds, cfg.AutoClearDelay)
+	if err != nil {
+		logger.Error("invalid sensitive content rules", "err", err)
+	} else {
+		manager.SetSensitivePolicy(sensitivePolicy)
+	}
+
+	manager.SetRetention(clipboard.RetentionPolicy{
+		MaxAge:        cfg.Retention.MaxAge,
+		MaxTotalBytes: cfg.Retention.MaxTotalBytes,
+		MaxItemSize:   cfg.Retention.MaxItemSize,
+		KindTTL:       cfg.Retention.KindTTL,
+	})
+}
+
+// monitorClipboard опрашивает буфер обмена; новый интервал опроса можно
+// передать через intervals
+func monitorClipboard(ctx context.Context, manager *clipboard.Manager, interval time.Duration, intervals <-chan time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// Без графического сеанса чтение ошибается на каждом шаге: одинаковые
+	// ошибки подряд записываем в журнал один раз
+	var lastErr string
+
 	// Сначала читаем текущее содержимое буфера обмена и устанавливаем его как последнее
 	initialContent, err := clipboard.GetClipboard()
 	if err != nil {
-		log.Printf("Ошибка начального чтения буфера обмена: %v", err)
+		logger.Warn("failed to read clipboard", "err", err)
+		lastErr = err.Error()
 	} else if initialContent != "" {
 		manager.SetLastContent(initialContent)
-		log.Printf("Initial clipboard content set: %s", initialContent[:min(20, len(initialContent))])
+		logger.Debug("initial clipboard content set", "size", len(initialContent))
 	}
+	for {
+		select {
+		case <-ticker.C:
+		case interval := <-intervals:
+			ticker.Reset(interval)
+			continue
+		case <-ctx.Done():
+			return
+		}
 
-	for range ticker.C {
 		content, err := clipboard.GetClipboard()
 		if err != nil {
-			log.Printf("Ошибка чтения буфера обмена: %v", err)
+			if err.Error() != lastErr {
+				logger.Warn("failed to read clipboard", "err", err)
+				lastErr = err.Error()
+			}
+			continue
+		}
+		lastErr = ""
+
+		// Во время паузы только запоминаем содержимое, чтобы после
+		// возобновления не сохранить то, что было скопировано в паузе
+		if manager.IsPaused() {
+			manager.SetLastContent(content)
 			continue
 		}
 
 		if content != "" {
-			manager.AddToHistory(content)
-			storeErr := store.SaveHistory(manager.GetHistory())
-			if storeErr != nil {
-				log.Printf("Ошибка сохранения истории: %v", storeErr)
-			}
+			// Подсказку источника проверяем только для нового содержимого
+			sensitiveHint := content != manager.GetLastContent() && clipboard.HasSensitiveHint()
+			manager.AddToHistoryWithHint(content, sensitiveHint)
 		}
 	}
 }
 
-func handleSyncMessages(manager *clipboard.Manager, store *storage.Storage, historyChan <-chan []types.ClipboardItem) {
-	for history := range historyChan {
-		log.Printf("Received %d history items via sync", len(history))
-		manager.ReplaceHistory(history)
-		err := store.SaveHistory(history)
-		if err != nil {
-			log.Printf("Error saving synced history: %v", err)
+func handleSyncMessages(ctx context.Context, manager *clipboard.Manager, historyChan <-chan []types.ClipboardItem) {
+	for {
+		select {
+		case history := <-historyChan:
+			logger.Info("history received via sync", "items", len(history))
+			// Сохранение выполняется через callback изменений менеджера
+			manager.MergeSyncedHistory(history)
+		case <-ctx.Done():
+			return
 		}
 	}
 }
+
+func startSessionWatcher(manager *clipboard.Manager, syncManager *sync.SyncManager, actions []string) *session.Watcher {
+	reactor, err := session.NewReactor(manager, syncManager, actions)
+	if err != nil {
+		logger.Error("invalid screen lock actions", "err", err)
+		return nil
+	}
+
+	watcher, err := session.Watch()
+	if err != nil {
+		logger.Warn("screen lock tracking is not available", "err", err)
+		return nil
+	}
+
+	go reactor.Run(watcher.Events())
+	return watcher
+}
+
+// startMaintenance запускает фоновое обслуживание истории. Задачи ничего
+// не делают, если с прошлого запуска ничего не изменилось.
+func startMaintenance(manager *clipboard.Manager, store *storage.Storage) *maintenance.Scheduler {
+	scheduler := maintenance.NewScheduler(
+		maintenance.Task{
+			Name:     "retention",
+			Interval: 30 * time.Second,
+			Run: func() error {
+				if removed := manager.ApplyRetention(time.Now()); removed > 0 {
+					logger.Info("retention policy applied", "removed", removed)
+				}
+				return nil
+			},
+		},
+		maintenance.Task{Name: "compaction", Interval: 10 * time.Minute, Run: store.Compact},
+		maintenance.Task{Name: "backup", Interval: time.Hour, Run: store.Backup},
+		maintenance.Task{
+			Name:     "blob gc",
+			Interval: 24 * time.Hour,
+			Run: func() error {
+				_, err := store.CollectGarbage()
+				return err
+			},
+		},
+	)
+	scheduler.Start()
+	return scheduler
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+)
+
+// maxMenuContent — содержимое крупнее этого размера в меню не загружается,
+// вместо него показывается превью
+const maxMenuContent = 64 * 1024
+
+var menuEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// runMenu выводит историю строками для dmenu/rofi/fzf, а с -select
+// копирует элемент по выбранной строке. С -with запускает меню сам.
+//
+//	smart-clipboard menu | rofi -dmenu | smart-clipboard menu -select
+//	smart-clipboard menu -with "fzf"
+func runMenu(args []string) error {
+	fs := flag.NewFlagSet("menu", flag.ContinueOnError)
+	selectMode := fs.Bool("select", false, "read the chosen line from standard input and copy its item")
+	with := fs.String("with", "", "run this menu command (for example \"rofi -dmenu\") and copy the chosen item")
+	print := fs.Bool("print", false, "print the chosen item instead of copying it")
+	width := fs.Int("width", 200, "maximum length of a line")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// Выбор читаем до подключения: без демона предыдущая команда конвейера
+	// держит историю, пока не выведет меню
+	var choice string
+	if *selectMode {
+		choice = strings.Join(rest, " ")
+		if choice == "" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			choice = string(data)
+		}
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	lines, err := menuLines(conn, *width)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *with != "":
+		choice, err := runMenuCommand(*with, lines)
+		if err != nil {
+			return err
+		}
+		return selectMenuLine(conn, lines, choice, *print)
+	case *selectMode:
+		return selectMenuLine(conn, lines, choice, *print)
+	default:
+		w := bufio.NewWriter(os.Stdout)
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		return w.Flush()
+	}
+}
+
+// menuLines строит строки меню в порядке истории менеджера: номер, двоеточие
+// и содержимое в одну строку с экранированными переводами строк
+func menuLines(conn caller, width int) ([]string, error) {
+	var items []ipc.Item
+	if err := conn.Call(ipc.MethodList, nil, &items); err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		text := item.Preview
+		if !item.Sensitive && item.Size <= maxMenuContent {
+			var full ipc.Item
+			if err := conn.Call(ipc.MethodGet, ipc.ItemRef{ID: item.ID}, &full); err != nil {
+				return nil, err
+			}
+			text = full.Content
+		}
+
+		text = menuEscaper.Replace(text)
+		if runes := []rune(text); len(runes) > width {
+			text = string(runes[:width]) + "…"
+		}
+		lines = append(lines, fmt.Sprintf("%d: %s", item.Index, text))
+	}
+	return lines, nil
+}
+
+// runMenuCommand передаёт строки меню команде и возвращает выбранную строку
+func runMenuCommand(command string, lines []string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	cmd.Stderr = os.Stderr
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		// dmenu, rofi и fzf завершаются с ошибкой, если выбор отменён
+		return "", exitCode(1)
+	}
+	return out.String(), nil
+}
+
+// selectMenuLine копирует элемент выбранной строки. Если история изменилась
+// после вывода меню, элемент ищется по тексту строки.
+func selectMenuLine(conn caller, lines []string, choice string, print bool) error {
+	choice = strings.TrimRight(choice, "\r\n")
+	if choice == "" {
+		return exitCode(1)
+	}
+
+	prefix, text, ok := strings.Cut(choice, ": ")
+	index, err := strconv.Atoi(prefix)
+	if !ok || err != nil {
+		return fmt.Errorf("not a menu line: %q", choice)
+	}
+
+	if index < 1 || index > len(lines) || lines[index-1] != choice {
+		index = 0
+		for i, line := range lines {
+			if _, lineText, _ := strings.Cut(line, ": "); lineText == text {
+				index = i + 1
+				break
+			}
+		}
+		if index == 0 {
+			return fmt.Errorf("the chosen item is no longer in history")
+		}
+	}
+
+	ref := ipc.ItemRef{Index: index}
+	if print {
+		var item ipc.Item
+		if err := conn.Call(ipc.MethodGet, ref, &item); err != nil {
+			return err
+		}
+		_, err := io.WriteString(os.Stdout, item.Content)
+		return err
+	}
+	return conn.Call(ipc.MethodCopy, ref, nil)
+}
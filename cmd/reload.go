@@ -0,0 +1,136 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gen2brain/beeep"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+	"github.com/yoshapihoff/smart-clipboard/internal/sync"
+	"github.com/yoshapihoff/smart-clipboard/internal/systemd"
+	"github.com/yoshapihoff/smart-clipboard/internal/tray"
+)
+
+// reloader применяет изменения конфигурации к работающему демону. Его
+// вызывает метод reload API — при изменении файла, по SIGHUP и командой
+// smart-clipboard reload, — поэтому он выполняется по очереди с остальными
+// вызовами API.
+type reloader struct {
+	live        *config.Live
+	manager     *clipboard.Manager
+	store       *storage.Storage
+	syncManager *sync.SyncManager
+
+	// intervals передаёт новый интервал опроса буфера обмена в monitorClipboard
+	intervals chan time.Duration
+}
+
+func newReloader(live *config.Live, manager *clipboard.Manager, store *storage.Storage, syncManager *sync.SyncManager) *reloader {
+	return &reloader{
+		live:        live,
+		manager:     manager,
+		store:       store,
+		syncManager: syncManager,
+		intervals:   make(chan time.Duration, 1),
+	}
+}
+
+// reload перечитывает конфигурацию и применяет изменения. Ошибочная
+// конфигурация не применяется целиком: демон продолжает работать с прежней,
+// а пользователь получает уведомление.
+func (r *reloader) reload() error {
+	systemd.Notify(systemd.StateReloading)
+	defer systemd.Notify(systemd.StateReady)
+
+	next, err := config.LoadConfig()
+	if err != nil {
+		logger.Error("invalid config, keeping the current one", "err", err)
+		notify("Configuration not applied: " + err.Error())
+		return err
+	}
+	prev := r.live.Load()
+
+	if next.Logging.File != prev.Logging.File || next.Logging.MaxSize != prev.Logging.MaxSize || next.Logging.MaxFiles != prev.Logging.MaxFiles {
+		if err := setupLogging(next); err != nil {
+			logger.Error("failed to set up logging", "err", err)
+		}
+	} else {
+		logging.SetLevels(next.Logging.Level, next.Logging.Levels)
+		logging.SetDebug(next.DebugMode)
+	}
+
+	// Историю переносим на новое место, а не начинаем заново
+	if next.StoragePath != prev.StoragePath {
+		if err := r.store.Move(next.StoragePath, r.manager.GetHistory()); err != nil {
+			logger.Error("failed to move history", "path", next.StoragePath, "err", err)
+			notify("History not moved to " + next.StoragePath + ": " + err.Error())
+			next.StoragePath = prev.StoragePath
+		} else {
+			logger.Info("history moved", "from", prev.StoragePath, "to", next.StoragePath)
+		}
+	}
+	r.store.SetBackupCount(next.BackupCount)
+	if err := r.store.SetEngine(next.StorageEngine); err != nil {
+		logger.Error("failed to select storage engine", "err", err)
+	}
+
+	r.manager.SetMaxItems(next.MaxItems)
+	configureManager(r.manager, next, r.store)
+	if r.store.SupportsLock() {
+		r.manager.SetIdleLock(next.IdleLockTimeout)
+	}
+
+	if r.syncManager != nil {
+		r.syncManager.SetEnabled(next.Sync.Enabled)
+	}
+
+	if next.CheckInterval != prev.CheckInterval {
+		select {
+		case <-r.intervals:
+		default:
+		}
+		r.intervals <- next.CheckInterval
+	}
+
+	r.live.Store(next)
+	tray.ApplyConfig(next)
+
+	if keys := restartKeys(prev, next); len(keys) > 0 {
+		logger.Warn("some changes take effect after restart", "keys", strings.Join(keys, ", "))
+	}
+	logger.Info("config reloaded")
+	return nil
+}
+
+// restartKeys перечисляет изменённые настройки, которые применяются
+// только при запуске демона
+func restartKeys(prev, next *config.Config) []string {
+	var keys []string
+	if prev.Encryption != next.Encryption {
+		keys = append(keys, "encryption")
+	}
+	if prev.EncryptionKeyFile != next.EncryptionKeyFile {
+		keys = append(keys, "encryption_key_file")
+	}
+	if !slices.Equal(prev.ScreenLockActions, next.ScreenLockActions) {
+		keys = append(keys, "screen_lock_actions")
+	}
+	if prev.Web != next.Web {
+		keys = append(keys, "web")
+	}
+	if prev.DBus != next.DBus {
+		keys = append(keys, "dbus")
+	}
+	return keys
+}
+
+// notify показывает уведомление рабочего стола. Без графического сеанса
+// оно не показывается, сообщение остаётся только в журнале.
+func notify(message string) {
+	beeep.Notify("Smart clipboard", message, "")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	gosync "sync"
+	"testing"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// TestReloadDuringSave проверяет под -race, что перезагрузка настроек
+// не гоняется с сохранением истории и ротацией резервных копий
+func TestReloadDuringSave(t *testing.T) {
+	dir := offlineEnv(t, "backup_count: 2\n")
+	historyPath := filepath.Join(dir, "history.json")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := storage.NewStorage(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := clipboard.NewManager(nil, cfg.MaxItems, nil)
+	r := newReloader(config.NewLive(cfg), manager, store, nil)
+
+	var wg gosync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			if err := r.reload(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		history := []types.ClipboardItem{{Content: "item", Preview: "item", Kind: types.KindText}}
+		for i := 0; i < 200; i++ {
+			if err := store.SaveHistory(history); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := store.Backup(); err != nil {
+				t.Error(err)
+				return
+			}
+			store.Verify()
+		}
+	}()
+	wg.Wait()
+}
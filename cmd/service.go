@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/systemd"
+)
+
+const (
+	serviceUnitName = "smart-clipboard.service"
+	socketUnitName  = "smart-clipboard.socket"
+
+	// statusInterval — как часто обновляется строка состояния без watchdog
+	statusInterval = 30 * time.Second
+)
+
+const serviceUnit = `[Unit]
+Description=Smart clipboard history daemon
+%[4]s
+[Service]
+Type=notify
+NotifyAccess=main
+ExecStart=%[1]s%[2]s
+ExecReload=/bin/kill -HUP $MAINPID
+Restart=on-failure
+WatchdogSec=60
+TimeoutStopSec=15
+
+[Install]
+WantedBy=%[3]s
+`
+
+// Сокет совпадает с ipc.SocketPath: %t — это $XDG_RUNTIME_DIR
+const socketUnit = `[Unit]
+Description=Smart clipboard control socket
+
+[Socket]
+ListenStream=%t/smart-clipboard/control.sock
+SocketMode=0600
+DirectoryMode=0700
+FileDescriptorName=control
+
+[Install]
+WantedBy=sockets.target
+`
+
+// runInstallService записывает unit-файлы службы пользователя systemd
+func runInstallService(args []string) error {
+	fs := flag.NewFlagSet("install-service", flag.ContinueOnError)
+	socket := fs.Bool("socket", false, "also install a socket unit that starts the daemon on the first command")
+	headless := fs.Bool("headless", false, "run the daemon without the tray icon and start it with the user session, not the desktop")
+	enable := fs.Bool("enable", false, "enable and start the units with systemctl")
+	print := fs.Bool("print", false, "print the units instead of writing them")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("systemd user services are only available on Linux")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if strings.ContainsAny(exe, " \t\"'\\") {
+		exe = strconv.Quote(exe)
+	}
+
+	// Значку в трее нужен графический сеанс: служба запускается и
+	// останавливается вместе с ним
+	flags, target := "", "graphical-session.target"
+	ordering := "PartOf=graphical-session.target\nAfter=graphical-session.target\n"
+	if *headless {
+		flags, target, ordering = " --headless", "default.target", ""
+	}
+
+	units := map[string]string{serviceUnitName: fmt.Sprintf(serviceUnit, exe, flags, target, ordering)}
+	if *socket {
+		units[socketUnitName] = socketUnit
+	}
+
+	if *print {
+		for _, name := range []string{serviceUnitName, socketUnitName} {
+			if unit, ok := units[name]; ok {
+				fmt.Printf("# %s\n%s\n", name, unit)
+			}
+		}
+		return nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return err
+	}
+	unitDir := filepath.Join(configDir, "systemd", "user")
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return err
+	}
+	for name, unit := range units {
+		path := filepath.Join(unitDir, name)
+		if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+
+	enableUnit := serviceUnitName
+	if *socket {
+		enableUnit = socketUnitName
+	}
+	if !*enable {
+		fmt.Printf("Run \"systemctl --user daemon-reload && systemctl --user enable --now %s\" to start it.\n", enableUnit)
+		return nil
+	}
+
+	for _, args := range [][]string{{"daemon-reload"}, {"enable", "--now", enableUnit}} {
+		cmd := exec.Command("systemctl", append([]string{"--user"}, args...)...)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("systemctl --user %s: %w", strings.Join(args, " "), err)
+		}
+	}
+	return nil
+}
+
+// startControlSocket начинает принимать команды на сокете от systemd, если
+// демон запущен активацией по сокету, иначе создаёт сокет сам
+func startControlSocket(api *ipc.Server) error {
+	listeners, err := systemd.Listeners()
+	if err != nil {
+		return err
+	}
+	if len(listeners) > 0 {
+		for _, extra := range listeners[1:] {
+			extra.Close()
+		}
+		logger.Info("control socket passed by systemd", "name", listeners[0].Name)
+		api.StartListener(listeners[0])
+		return nil
+	}
+	return api.Start()
+}
+
+// notifySystemd сообщает systemd о готовности, а затем обновляет строку
+// состояния и отвечает watchdog, пока API демона отвечает на запросы.
+// Без systemd сразу возвращается.
+func notifySystemd(ctx context.Context, api *ipc.Server) {
+	if ok, err := systemd.Notify(systemd.StateReady, systemd.Status(serviceStatus(api))); err != nil {
+		logger.Warn("failed to notify systemd", "err", err)
+		return
+	} else if !ok {
+		return
+	}
+
+	interval := statusInterval
+	watchdog := systemd.WatchdogInterval()
+	if watchdog > 0 {
+		interval = min(interval, watchdog/2)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			states := []string{systemd.Status(serviceStatus(api))}
+			if watchdog > 0 {
+				states = append(states, systemd.StateWatchdog)
+			}
+			if _, err := systemd.Notify(states...); err != nil {
+				logger.Warn("failed to notify systemd", "err", err)
+			}
+		case <-ctx.Done():
+			systemd.Notify(systemd.StateStopping, systemd.Status("Stopping"))
+			return
+		}
+	}
+}
+
+// serviceStatus описывает состояние демона для systemctl status. Вызов
+// идёт через API, поэтому зависший демон не отвечает и watchdog срабатывает.
+func serviceStatus(api *ipc.Server) string {
+	var status ipc.Status
+	if err := api.Call(ipc.MethodStatus, nil, &status); err != nil {
+		return "Status unavailable: " + err.Error()
+	}
+
+	capture := "capturing"
+	switch {
+	case status.Locked:
+		capture = "history locked"
+	case status.Paused:
+		capture = "paused"
+	}
+
+	syncState := "sync off"
+	if status.Sync {
+		syncState = fmt.Sprintf("%d sync peers", status.Peers)
+	}
+	return fmt.Sprintf("%d items, %s, %s", status.Items, syncState, capture)
+}
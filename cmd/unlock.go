@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/prompt"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+)
+
+// passphraseEnv позволяет передать пароль без интерактивного ввода
+const passphraseEnv = "SMART_CLIPBOARD_PASSPHRASE"
+
+// maxUnlockAttempts ограничивает число попыток ввода пароля
+const maxUnlockAttempts = 3
+
+// unlockStorage разблокирует зашифрованное хранилище или включает шифрование,
+// если оно задано в конфигурации. Для файла в открытом виде без шифрования
+// в конфигурации ничего не делает.
+func unlockStorage(store *storage.Storage, cfg *config.Config) error {
+	encrypted, err := store.IsEncrypted()
+	if err != nil {
+		return err
+	}
+	if !encrypted && !cfg.Encryption {
+		return nil
+	}
+
+	if cfg.EncryptionKeyFile != "" {
+		return store.Unlock(storage.Secret{KeyFile: cfg.EncryptionKeyFile})
+	}
+
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return store.Unlock(storage.Secret{Passphrase: []byte(passphrase)})
+	}
+
+	if !encrypted {
+		// Первое включение шифрования — пароль вводится дважды
+		passphrase, err := prompt.NewPassphrase("New smart-clipboard passphrase")
+		if err != nil {
+			return err
+		}
+		return store.Unlock(storage.Secret{Passphrase: passphrase})
+	}
+
+	for attempt := 1; ; attempt++ {
+		passphrase, err := prompt.Passphrase("Smart-clipboard passphrase")
+		if err != nil {
+			return err
+		}
+
+		err = store.Unlock(storage.Secret{Passphrase: passphrase})
+		if !errors.Is(err, storage.ErrWrongKey) || attempt == maxUnlockAttempts {
+			return err
+		}
+	}
+}
+
+// unlockExisting разблокирует уже зашифрованное хранилище для служебных
+// команд. Файл в открытом виде они не шифруют: если шифрование включено,
+// но история ещё не зашифрована, команда завершается ошибкой.
+func unlockExisting(store *storage.Storage, cfg *config.Config) error {
+	encrypted, err := store.IsEncrypted()
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		return unlockStorage(store, cfg)
+	}
+	if cfg.Encryption {
+		return fmt.Errorf("encryption is enabled but the history is not encrypted yet; start smart-clipboard once to encrypt it")
+	}
+	return nil
+}
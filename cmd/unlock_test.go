@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+func TestUnlockExistingDoesNotEncrypt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store, err := storage.NewStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveHistory([]types.ClipboardItem{{Content: "plain"}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(passphraseEnv, "pass")
+
+	if err := unlockExisting(store, &config.Config{}); err != nil {
+		t.Fatalf("plaintext history without encryption: %v", err)
+	}
+	if err := unlockExisting(store, &config.Config{Encryption: true}); err == nil {
+		t.Fatal("plaintext history with encryption enabled was accepted")
+	}
+	if encrypted, _ := store.IsEncrypted(); encrypted {
+		t.Fatal("history was encrypted as a side effect")
+	}
+
+	if err := store.Unlock(storage.Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := storage.NewStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unlockExisting(reopened, &config.Config{Encryption: true}); err != nil {
+		t.Fatalf("encrypted history: %v", err)
+	}
+	if _, err := reopened.LoadHistory(); err != nil {
+		t.Fatalf("history is still locked: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/web"
+)
+
+// startWebServer запускает веб-интерфейс поверх API демона
+func startWebServer(api *ipc.Server, cfg *config.Config) *web.Server {
+	token, err := web.LoadToken(config.WebTokenPath())
+	if err != nil {
+		logger.Error("failed to read web UI token", "err", err)
+		return nil
+	}
+
+	server, err := web.NewServer(api, cfg.Web.Listen, token)
+	if err != nil {
+		logger.Error("failed to configure web UI", "err", err)
+		return nil
+	}
+	if err := server.Start(); err != nil {
+		logger.Error("failed to start web UI", "err", err)
+		return nil
+	}
+	logger.Info("web UI started", "url", "http://"+cfg.Web.Listen+"/")
+	return server
+}
+
+// runWeb выводит ссылку на веб-интерфейс с токеном доступа, а с -open открывает её.
+// С -token выводит только токен для HTTP API (заголовок Authorization: Bearer).
+func runWeb(args []string) error {
+	fs := flag.NewFlagSet("web", flag.ContinueOnError)
+	open := fs.Bool("open", false, "open the web UI in the browser")
+	tokenOnly := fs.Bool("token", false, "print only the bearer token for the HTTP API")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !cfg.Web.Enabled {
+		fmt.Fprintln(os.Stderr, "The web UI is disabled, set web.enabled: true in config.yaml and restart smart-clipboard.")
+	}
+
+	token, err := web.LoadToken(config.WebTokenPath())
+	if err != nil {
+		return err
+	}
+	if *tokenOnly {
+		fmt.Println(token)
+		return nil
+	}
+	link := web.URL(cfg.Web.Listen, token)
+
+	if !*open {
+		fmt.Println(link)
+		return nil
+	}
+	return openBrowser(link)
+}
+
+func openBrowser(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	return cmd.Start()
+}
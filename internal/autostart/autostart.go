@@ -0,0 +1,157 @@
+// Package autostart управляет ярлыками freedesktop для приложения: ярлыком
+// запуска в $XDG_DATA_HOME/applications со значком и ярлыком XDG autostart
+// в $XDG_CONFIG_HOME/autostart, который запускает демон при входе в систему.
+// Ярлыки используются в Linux и BSD, на остальных платформах они не
+// поддерживаются.
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	appID     = "smart-clipboard"
+	entryName = appID + ".desktop"
+)
+
+// ErrUnsupported возвращается на платформах без ярлыков freedesktop
+var ErrUnsupported = errors.New("desktop entries are not supported on " + runtime.GOOS)
+
+const entryTemplate = `[Desktop Entry]
+Type=Application
+Version=1.5
+Name=Smart Clipboard
+GenericName=Clipboard Manager
+Comment=Clipboard history with search and sync
+Exec=%s
+Icon=%s
+Terminal=false
+Categories=Utility;
+Keywords=clipboard;history;paste;
+StartupNotify=false
+`
+
+// Supported сообщает, используются ли на платформе ярлыки freedesktop
+func Supported() bool {
+	return runtime.GOOS != "darwin" && runtime.GOOS != "windows"
+}
+
+// Enabled сообщает, запускается ли демон при входе в систему. Ярлык,
+// скрытый пользователем (Hidden=true), считается выключенным.
+func Enabled() bool {
+	path, err := autostartPath()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "Hidden=true" {
+			return false
+		}
+	}
+	return true
+}
+
+// Enable устанавливает ярлык приложения со значком и включает запуск демона
+// при входе в систему. exe — путь к исполняемому файлу, icon — PNG 256x256.
+func Enable(exe string, icon []byte) error {
+	if err := InstallApplication(exe, icon); err != nil {
+		return err
+	}
+
+	path, err := autostartPath()
+	if err != nil {
+		return err
+	}
+	return writeEntry(path, exe)
+}
+
+// Disable удаляет ярлык автозапуска; ярлык приложения остаётся
+func Disable() error {
+	if !Supported() {
+		return ErrUnsupported
+	}
+	path, err := autostartPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// InstallApplication записывает ярлык приложения и значок, чтобы
+// smart-clipboard появился в меню приложений рабочего стола
+func InstallApplication(exe string, icon []byte) error {
+	if !Supported() {
+		return ErrUnsupported
+	}
+
+	dataHome, err := dataHome()
+	if err != nil {
+		return err
+	}
+
+	iconPath := filepath.Join(dataHome, "icons", "hicolor", "256x256", "apps", appID+".png")
+	if err := os.MkdirAll(filepath.Dir(iconPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(iconPath, icon, 0644); err != nil {
+		return err
+	}
+
+	return writeEntry(filepath.Join(dataHome, "applications", entryName), exe)
+}
+
+func writeEntry(path, exe string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	entry := fmt.Sprintf(entryTemplate, quoteExec(exe), appID)
+	return os.WriteFile(path, []byte(entry), 0644)
+}
+
+func autostartPath() (string, error) {
+	if !Supported() {
+		return "", ErrUnsupported
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "autostart", entryName), nil
+}
+
+// dataHome возвращает $XDG_DATA_HOME или ~/.local/share
+func dataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// quoteExec экранирует путь для ключа Exec по спецификации desktop entry:
+// аргумент с зарезервированными символами заключается в кавычки, внутри
+// которых экранируются ", `, $ и \; знак % удваивается всегда
+func quoteExec(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if !strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`") {
+		return arg
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`).Replace(arg)
+	// В значении ключа обратная косая черта экранируется ещё раз
+	return `"` + strings.ReplaceAll(escaped, `\`, `\\`) + `"`
+}
@@ -1,33 +1,194 @@
 package clipboard
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	gosync "sync"
 	"time"
 
+	"github.com/gen2brain/beeep"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
 	"github.com/yoshapihoff/smart-clipboard/internal/sync"
 	"github.com/yoshapihoff/smart-clipboard/internal/types"
 )
 
+var logger = logging.For("clipboard")
+
+// ErrNotFound возвращается, если выбранного элемента нет в истории
+var ErrNotFound = errors.New("item not found")
+
+// Доступ к системному буферу обмена; тесты подменяют его
+var (
+	readClipboard  = GetClipboard
+	writeClipboard = SetClipboard
+)
+
 type Manager struct {
+	// mu защищает историю и настройки ниже вплоть до блокировки истории.
+	// Изменения истории копятся в changes и передаются onChange после снятия
+	// mu. Каждая пачка изменений получает под mu номер, и пачки передаются
+	// строго по номерам, поэтому порядок изменений сохраняется.
+	mu         gosync.Mutex
+	changes    []types.Change
+	nextBatch  uint64
+	notifyMu   gosync.Mutex
+	notifyCond *gosync.Cond
+	notified   uint64 // Номер следующей пачки для onChange
+
 	history        []types.ClipboardItem
 	maxHistorySize int
 	syncManager    *sync.SyncManager
 	lastContent    string // Отслеживаем последнее содержимое буфера обмена
+
+	// Состояние паузы захвата
+	pauseMu       gosync.Mutex
+	paused        bool
+	pausedUntil   time.Time // Нулевое значение означает паузу до ручного возобновления
+	resumeTimer   *time.Timer
+	onPauseChange func(paused bool)
+
+	// Политика для конфиденциальных элементов и таймер автоочистки
+	sensitivePolicy *SensitivePolicy
+	autoClearMu     gosync.Mutex
+	autoClearTimer  *time.Timer
+
+	// Блокировка истории
+	lockMu       gosync.Mutex
+	locked       bool
+	lockedBuffer []types.ClipboardItem // Захваченное во время блокировки
+	idleTimeout  time.Duration
+	idleTimer    *time.Timer
+	onLockChange func(locked bool)
+
+	// Вызывается при каждом изменении истории (для журнала хранилища)
+	onChange func(change types.Change)
+
+	// Хранилище блобов для содержимого крупнее blobThreshold байт
+	blobs         BlobStore
+	blobThreshold int
+
+	retention RetentionPolicy
+}
+
+// Selector выбирает элемент истории: возвращает его индекс или -1. Вызывается
+// под блокировкой истории, поэтому найденный элемент не может измениться
+// до того, как с ним что-то сделают.
+type Selector func(history []types.ClipboardItem) int
+
+// ByID выбирает элемент по ID (см. ClipboardItem.ID)
+func ByID(id string) Selector {
+	return func(history []types.ClipboardItem) int {
+		for i, item := range history {
+			if item.ID() == id {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+// BlobStore хранит крупное содержимое вне истории по его SHA-256
+type BlobStore interface {
+	Put(data []byte) (string, error)
+	Get(hash string) ([]byte, error)
 }
 
 func NewManager(initialHistory []types.ClipboardItem, maxSize int, syncManager *sync.SyncManager) *Manager {
-	return &Manager{
+	m := &Manager{
 		history:        initialHistory,
 		maxHistorySize: maxSize,
 		syncManager:    syncManager,
 	}
+	m.notifyCond = gosync.NewCond(&m.notifyMu)
+	return m
+}
+
+// SetMaxItems меняет предельный размер истории; лишние элементы удаляются
+func (m *Manager) SetMaxItems(size int) {
+	if size <= 0 {
+		return
+	}
+	m.acquire()
+	defer m.release()
+	m.maxHistorySize = size
+
+	if len(m.history) > size {
+		for _, dropped := range m.history[size:] {
+			m.notifyChange(types.Change{Op: types.OpDelete, Content: dropped.ID()})
+		}
+		m.history = m.history[:size]
+	}
+}
+
+// SetBlobStore включает хранение содержимого крупнее threshold байт в хранилище блобов
+func (m *Manager) SetBlobStore(blobs BlobStore, threshold int) {
+	m.acquire()
+	defer m.release()
+	m.blobs = blobs
+	m.blobThreshold = threshold
+}
+
+// ItemContent возвращает полное содержимое элемента, при необходимости
+// загружая его из хранилища блобов
+func (m *Manager) ItemContent(item types.ClipboardItem) (string, error) {
+	if item.BlobHash == "" {
+		return item.Content, nil
+	}
+	m.acquire()
+	blobs := m.blobs
+	m.release()
+	if blobs == nil {
+		return "", fmt.Errorf("item %s is stored as a blob, but no blob store is configured", item.BlobHash)
+	}
+
+	data, err := blobs.Get(item.BlobHash)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SyncableHistory возвращает историю без элементов из хранилища блобов:
+// их содержимого нет в самой истории, и оно не поместилось бы в UDP-пакет
+func (m *Manager) SyncableHistory() []types.ClipboardItem {
+	m.acquire()
+	defer m.release()
+	return m.syncableHistory()
+}
+
+func (m *Manager) syncableHistory() []types.ClipboardItem {
+	history := make([]types.ClipboardItem, 0, len(m.history))
+	for _, item := range m.history {
+		if item.BlobHash == "" {
+			history = append(history, item)
+		}
+	}
+	return history
+}
+
+// SetSensitivePolicy устанавливает политику для конфиденциальных элементов
+func (m *Manager) SetSensitivePolicy(policy *SensitivePolicy) {
+	m.acquire()
+	defer m.release()
+	m.sensitivePolicy = policy
 }
 
 // AddToHistory добавляет содержимое в историю и отправляет по сети только если содержимое изменилось
 func (m *Manager) AddToHistory(content string) {
+	m.AddToHistoryWithHint(content, false)
+}
+
+// AddToHistoryWithHint работает как AddToHistory, но дополнительно учитывает
+// признак конфиденциальности, выставленный источником буфера обмена
+func (m *Manager) AddToHistoryWithHint(content string, sensitiveHint bool) {
 	if content == "" {
 		return
 	}
 
+	m.acquire()
+	defer m.release()
+
 	// Проверяем, изменилось ли содержимое буфера обмена
 	if content == m.lastContent {
 		return // Содержимое не изменилось, ничего не делаем
@@ -36,67 +197,271 @@ func (m *Manager) AddToHistory(content string) {
 	// Обновляем последнее содержимое
 	m.lastContent = content
 
-	var existingClickCount int
-	found := false
+	// Слишком крупное содержимое не сохраняется
+	if m.tooLarge(content) {
+		return
+	}
 
-	// Проверяем, есть ли уже такой элемент в истории
-	for _, item := range m.history {
-		if item.Content == content {
-			// Если элемент найден, сохраняем счётчик кликов и удаляем старый
-			existingClickCount = item.ClickCount
-			found = true
-			m.removeFromHistory(content)
-			break
-		}
+	item := m.newItem(content, sensitiveHint)
+	if item.Sensitive {
+		m.scheduleAutoClear(content)
 	}
+	m.addItem(item)
+}
 
+// Add добавляет в историю содержимое, пришедшее не из системного буфера
+// обмена (например, из командной строки). Буфер обмена не меняется.
+func (m *Manager) Add(content string) (types.ClipboardItem, error) {
+	if content == "" {
+		return types.ClipboardItem{}, fmt.Errorf("content is empty")
+	}
+
+	m.acquire()
+	defer m.release()
+	if m.tooLarge(content) {
+		return types.ClipboardItem{}, fmt.Errorf("content exceeds the maximum item size of %d bytes", m.retention.MaxItemSize)
+	}
+
+	item := m.newItem(content, false)
+	m.addItem(item)
+	return item, nil
+}
+
+// newItem создаёт элемент истории: определяет вид и конфиденциальность
+// содержимого и переносит крупное содержимое в хранилище блобов
+func (m *Manager) newItem(content string, sensitiveHint bool) types.ClipboardItem {
+	kind := DetectKind(content)
 	item := types.ClipboardItem{
-		Content:    content,
-		Timestamp:  time.Now(),
-		Preview:    getPreview(content),
-		ClickCount: existingClickCount,
+		Content:   content,
+		Timestamp: time.Now(),
+		Preview:   getPreview(content),
+		Kind:      kind,
+		Sensitive: sensitiveHint || m.sensitivePolicy.IsSensitive(content, kind),
+	}
+
+	// Крупное содержимое уходит в хранилище блобов, в истории остаётся ссылка
+	if m.blobs != nil && m.blobThreshold > 0 && len(content) > m.blobThreshold {
+		hash, err := m.blobs.Put([]byte(content))
+		if err != nil {
+			logger.Warn("failed to store blob, keeping the item inline", "err", err)
+		} else {
+			item.Content = ""
+			item.BlobHash = hash
+			item.Size = len(content)
+		}
+	}
+	return item
+}
+
+// addItem помещает элемент в историю, а пока история заблокирована —
+// в буфер только на запись
+func (m *Manager) addItem(item types.ClipboardItem) {
+	m.lockMu.Lock()
+	if m.locked {
+		m.lockedBuffer = append(m.lockedBuffer, item)
+		m.lockMu.Unlock()
+		return
 	}
+	m.lockMu.Unlock()
 
-	// Если элемент не был найден, добавляем его в историю
-	if !found {
-		m.history = append([]types.ClipboardItem{item}, m.history...)
-	} else {
-		// Если элемент был найден, вставляем его в начало с сохранённым счётчиком
-		m.history = append([]types.ClipboardItem{item}, m.history...)
+	m.insertItem(item)
+}
+
+// insertItem помещает элемент в начало истории, сохраняя счётчик кликов
+// существующего элемента с тем же содержимым, и отправляет историю по сети
+func (m *Manager) insertItem(item types.ClipboardItem) {
+	// Если такой элемент уже есть, сохраняем счётчик кликов и удаляем старый
+	if i := ByID(item.ID())(m.history); i >= 0 {
+		item.ClickCount = m.history[i].ClickCount
+		m.removeAt(i)
 	}
 
+	m.history = append([]types.ClipboardItem{item}, m.history...)
+
 	// Сортируем историю: сначала по количеству кликов (по убыванию), затем по времени (по убыванию)
 	m.sortHistory()
+	m.notifyChange(types.Change{Op: types.OpAdd, Item: &item})
 
 	// Ограничение размера истории
 	if len(m.history) > m.maxHistorySize {
+		for _, dropped := range m.history[m.maxHistorySize:] {
+			m.notifyChange(types.Change{Op: types.OpDelete, Content: dropped.ID()})
+		}
 		m.history = m.history[:m.maxHistorySize]
 	}
 
-	// Отправляем историю по сети
+	m.sendHistory()
+}
+
+// sendHistory отправляет историю по сети
+func (m *Manager) sendHistory() {
 	if m.syncManager != nil {
-		go m.syncManager.SendHistory(m.history)
+		go m.syncManager.SendHistory(m.syncableHistory())
+	}
+}
+
+// Lock блокирует просмотр истории. Захват продолжается в буфер только на запись,
+// который переносится в историю при разблокировке.
+func (m *Manager) Lock() {
+	m.lockMu.Lock()
+	if m.locked {
+		m.lockMu.Unlock()
+		return
+	}
+	m.locked = true
+	if m.idleTimer != nil {
+		m.idleTimer.Stop()
+	}
+	callback := m.onLockChange
+	m.lockMu.Unlock()
+
+	if callback != nil {
+		callback(true)
 	}
 }
 
+// Unlock снимает блокировку и переносит накопленные элементы в историю.
+// Проверка пароля выполняется вызывающей стороной.
+func (m *Manager) Unlock() {
+	m.lockMu.Lock()
+	if !m.locked {
+		m.lockMu.Unlock()
+		return
+	}
+	m.locked = false
+	pending := m.lockedBuffer
+	m.lockedBuffer = nil
+	callback := m.onLockChange
+	m.lockMu.Unlock()
+
+	m.acquire()
+	for _, item := range pending {
+		m.insertItem(item)
+	}
+	m.release()
+	m.Touch()
+
+	if callback != nil {
+		callback(false)
+	}
+}
+
+// IsLocked сообщает, заблокирована ли история
+func (m *Manager) IsLocked() bool {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	return m.locked
+}
+
+// SetIdleLock включает автоматическую блокировку после timeout без действий
+// пользователя. Нулевое значение отключает автоблокировку.
+func (m *Manager) SetIdleLock(timeout time.Duration) {
+	m.lockMu.Lock()
+	m.idleTimeout = timeout
+	m.lockMu.Unlock()
+	m.Touch()
+}
+
+// Touch отмечает действие пользователя с историей и перезапускает таймер автоблокировки
+func (m *Manager) Touch() {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+
+	if m.idleTimer != nil {
+		m.idleTimer.Stop()
+		m.idleTimer = nil
+	}
+	if m.idleTimeout > 0 && !m.locked {
+		m.idleTimer = time.AfterFunc(m.idleTimeout, m.Lock)
+	}
+}
+
+// SetLockCallback устанавливает функцию, вызываемую при блокировке и разблокировке
+func (m *Manager) SetLockCallback(callback func(locked bool)) {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	m.onLockChange = callback
+}
+
+// Pause приостанавливает захват буфера обмена на duration.
+// Если duration равен нулю, пауза длится до вызова Resume.
+func (m *Manager) Pause(duration time.Duration) {
+	m.pauseMu.Lock()
+	if m.resumeTimer != nil {
+		m.resumeTimer.Stop()
+		m.resumeTimer = nil
+	}
+	m.paused = true
+	m.pausedUntil = time.Time{}
+	if duration > 0 {
+		m.pausedUntil = time.Now().Add(duration)
+		m.resumeTimer = time.AfterFunc(duration, m.Resume)
+	}
+	callback := m.onPauseChange
+	m.pauseMu.Unlock()
+
+	if callback != nil {
+		callback(true)
+	}
+}
+
+// Resume возобновляет захват буфера обмена
+func (m *Manager) Resume() {
+	m.pauseMu.Lock()
+	if m.resumeTimer != nil {
+		m.resumeTimer.Stop()
+		m.resumeTimer = nil
+	}
+	wasPaused := m.paused
+	m.paused = false
+	m.pausedUntil = time.Time{}
+	callback := m.onPauseChange
+	m.pauseMu.Unlock()
+
+	if wasPaused && callback != nil {
+		callback(false)
+	}
+}
+
+// IsPaused сообщает, приостановлен ли захват буфера обмена
+func (m *Manager) IsPaused() bool {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	return m.paused
+}
+
+// PausedUntil возвращает время автоматического возобновления захвата.
+// Нулевое значение означает, что пауза бессрочная или не активна.
+func (m *Manager) PausedUntil() time.Time {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	return m.pausedUntil
+}
+
+// SetPauseCallback устанавливает функцию, вызываемую при смене состояния паузы
+func (m *Manager) SetPauseCallback(callback func(paused bool)) {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	m.onPauseChange = callback
+}
+
 // SetLastContent устанавливает последнее известное содержимое буфера обмена
 func (m *Manager) SetLastContent(content string) {
+	m.acquire()
+	defer m.release()
 	m.lastContent = content
 }
 
 // GetLastContent возвращает последнее известное содержимое буфера обмена
 func (m *Manager) GetLastContent() string {
+	m.acquire()
+	defer m.release()
 	return m.lastContent
 }
 
-// removeFromHistory удаляет элемент из истории по содержимому
-func (m *Manager) removeFromHistory(content string) {
-	for i, item := range m.history {
-		if item.Content == content {
-			m.history = append(m.history[:i], m.history[i+1:]...)
-			break
-		}
-	}
+// removeAt удаляет элемент истории с индексом i
+func (m *Manager) removeAt(i int) {
+	m.history = append(m.history[:i], m.history[i+1:]...)
 }
 
 // sortHistory сортирует историю: сначала по количеству кликов (по убыванию), затем по времени (по убыванию)
@@ -123,43 +488,271 @@ func (m *Manager) shouldSwap(a, b types.ClipboardItem) bool {
 	return a.Timestamp.Before(b.Timestamp)
 }
 
+// GetHistory возвращает копию истории
 func (m *Manager) GetHistory() []types.ClipboardItem {
-	return m.history
+	m.acquire()
+	defer m.release()
+	return slices.Clone(m.history)
+}
+
+// Item возвращает выбранный элемент и его индекс в истории
+func (m *Manager) Item(sel Selector) (types.ClipboardItem, int, bool) {
+	m.acquire()
+	defer m.release()
+	i := sel(m.history)
+	if i < 0 {
+		return types.ClipboardItem{}, -1, false
+	}
+	return m.history[i], i, true
 }
 
 func (m *Manager) ClearHistory() {
+	m.acquire()
+	defer m.release()
 	m.history = []types.ClipboardItem{}
+	m.notifyChange(types.Change{Op: types.OpClear})
+}
+
+// DeleteItem удаляет выбранный элемент из истории. Возвращает false, если элемента нет.
+func (m *Manager) DeleteItem(sel Selector) bool {
+	m.acquire()
+	defer m.release()
+	return m.deleteItem(sel)
+}
+
+func (m *Manager) deleteItem(sel Selector) bool {
+	i := sel(m.history)
+	if i < 0 {
+		return false
+	}
+	id := m.history[i].ID()
+	m.removeAt(i)
+	m.notifyChange(types.Change{Op: types.OpDelete, Content: id})
+	return true
+}
+
+// SetPinned закрепляет или открепляет выбранный элемент. Возвращает false, если элемента нет.
+func (m *Manager) SetPinned(sel Selector, pinned bool) bool {
+	m.acquire()
+	defer m.release()
+	i := sel(m.history)
+	if i < 0 {
+		return false
+	}
+	m.history[i].Pinned = pinned
+	m.notifyChange(types.Change{Op: types.OpPin, Content: m.history[i].ID(), Pinned: pinned})
+	return true
+}
+
+// SetTags заменяет теги выбранного элемента. Возвращает false, если элемента нет.
+func (m *Manager) SetTags(sel Selector, tags []string) bool {
+	m.acquire()
+	defer m.release()
+	i := sel(m.history)
+	if i < 0 {
+		return false
+	}
+	m.history[i].Tags = tags
+	m.notifyChange(types.Change{Op: types.OpTag, Content: m.history[i].ID(), Tags: tags})
+	return true
+}
+
+// EditItem заменяет содержимое выбранного элемента, сохраняя его место,
+// счётчик кликов, закрепление и теги. Элемент с таким же новым содержимым
+// удаляется. Возвращает изменённый элемент.
+func (m *Manager) EditItem(sel Selector, content string) (types.ClipboardItem, error) {
+	if content == "" {
+		return types.ClipboardItem{}, fmt.Errorf("content is empty")
+	}
+
+	m.acquire()
+	defer m.release()
+	if m.tooLarge(content) {
+		return types.ClipboardItem{}, fmt.Errorf("content exceeds the maximum item size of %d bytes", m.retention.MaxItemSize)
+	}
+
+	i := sel(m.history)
+	if i < 0 {
+		return types.ClipboardItem{}, ErrNotFound
+	}
+	old := m.history[i]
+	id := old.ID()
+
+	item := m.newItem(content, old.Sensitive)
+	item.Timestamp = old.Timestamp
+	item.ClickCount = old.ClickCount
+	item.Pinned = old.Pinned
+	item.Tags = old.Tags
+
+	if item.ID() != id {
+		if j := ByID(item.ID())(m.history); j >= 0 {
+			m.removeAt(j)
+		}
+	}
+	m.history[ByID(id)(m.history)] = item
+	m.notifyChange(types.Change{Op: types.OpEdit, Content: id, Item: &item})
+
+	m.sendHistory()
+	return item, nil
+}
+
+// SetChangeCallback устанавливает функцию, вызываемую при каждом изменении истории
+func (m *Manager) SetChangeCallback(callback func(change types.Change)) {
+	m.acquire()
+	defer m.release()
+	m.onChange = callback
+}
+
+// notifyChange запоминает изменение истории; onChange получит его при release
+func (m *Manager) notifyChange(change types.Change) {
+	m.changes = append(m.changes, change)
+}
+
+// acquire блокирует историю; снимается только через release
+func (m *Manager) acquire() {
+	m.mu.Lock()
+}
+
+// release снимает блокировку истории и передаёт накопленные изменения
+// в onChange. Callback вызывается без блокировки истории и может её читать,
+// но не менять: изменение ждало бы окончания текущей пачки.
+func (m *Manager) release() {
+	changes, callback := m.changes, m.onChange
+	m.changes = nil
+	if len(changes) == 0 || callback == nil {
+		m.mu.Unlock()
+		return
+	}
+	batch := m.nextBatch
+	m.nextBatch++
+	m.mu.Unlock()
+
+	// Ждём, пока переданы все пачки с меньшими номерами
+	m.notifyMu.Lock()
+	for m.notified != batch {
+		m.notifyCond.Wait()
+	}
+	m.notifyMu.Unlock()
+
+	for _, change := range changes {
+		callback(change)
+	}
+
+	m.notifyMu.Lock()
+	m.notified++
+	m.notifyCond.Broadcast()
+	m.notifyMu.Unlock()
 }
 
 func (m *Manager) CopyToClipboard(content string) error {
-	return SetClipboard(content)
+	if err := writeClipboard(content); err != nil {
+		return err
+	}
+
+	m.acquire()
+	defer m.release()
+	var hash string
+	for _, item := range m.history {
+		if !item.Sensitive {
+			continue
+		}
+		if item.BlobHash != "" && hash == "" {
+			hash = types.ContentHash([]byte(content))
+		}
+		if item.Content == content || (item.BlobHash != "" && item.BlobHash == hash) {
+			m.scheduleAutoClear(content)
+			break
+		}
+	}
+	return nil
+}
+
+// scheduleAutoClear очищает системный буфер обмена через заданную задержку,
+// если к этому моменту в нём всё ещё находится content
+func (m *Manager) scheduleAutoClear(content string) {
+	if m.sensitivePolicy == nil || m.sensitivePolicy.AutoClearDelay <= 0 {
+		return
+	}
+
+	m.autoClearMu.Lock()
+	defer m.autoClearMu.Unlock()
+
+	if m.autoClearTimer != nil {
+		m.autoClearTimer.Stop()
+	}
+	m.autoClearTimer = time.AfterFunc(m.sensitivePolicy.AutoClearDelay, func() {
+		current, err := readClipboard()
+		if err != nil {
+			logger.Warn("failed to read clipboard before auto-clear", "err", err)
+			return
+		}
+		if current != content {
+			return
+		}
+
+		if err := m.ClearClipboard(); err != nil {
+			logger.Warn("failed to auto-clear clipboard", "err", err)
+			return
+		}
+		beeep.Notify("Smart clipboard", "Sensitive item cleared from clipboard", "")
+	})
 }
 
 // ClearClipboard очищает содержимое системного буфера обмена
 func (m *Manager) ClearClipboard() error {
-	return SetClipboard("")
+	return writeClipboard("")
 }
 
-// IncrementClickCount увеличивает счётчик кликов для элемента и пересортировывает историю
-func (m *Manager) IncrementClickCount(content string) {
-	for i, item := range m.history {
-		if item.Content == content {
-			m.history[i].ClickCount++
-			// Пересортировываем историю после изменения счётчика
-			m.sortHistory()
-			break
-		}
+// IncrementClickCount увеличивает счётчик кликов выбранного элемента и пересортировывает историю
+func (m *Manager) IncrementClickCount(sel Selector) {
+	m.acquire()
+	defer m.release()
+	i := sel(m.history)
+	if i < 0 {
+		return
 	}
+	m.history[i].ClickCount++
+	m.notifyChange(types.Change{Op: types.OpUse, Content: m.history[i].ID(), ClickCount: m.history[i].ClickCount})
+	// Пересортировываем историю после изменения счётчика
+	m.sortHistory()
 }
 
 func (m *Manager) ReplaceHistory(history []types.ClipboardItem) {
-	m.history = history
+	m.acquire()
+	defer m.release()
+	m.replaceHistory(history)
+}
+
+// MergeSyncedHistory заменяет историю пришедшей по сети, сохраняя локальные
+// элементы из хранилища блобов: по сети они не передаются, и без них сборка
+// мусора удалила бы их содержимое
+func (m *Manager) MergeSyncedHistory(history []types.ClipboardItem) {
+	m.acquire()
+	defer m.release()
+
+	merged := slices.Clone(history)
+	seen := make(map[string]bool, len(history))
+	for _, item := range history {
+		seen[item.ID()] = true
+	}
+	for _, item := range m.history {
+		if item.BlobHash != "" && !seen[item.ID()] {
+			merged = append(merged, item)
+		}
+	}
+	m.replaceHistory(merged)
+}
+
+func (m *Manager) replaceHistory(history []types.ClipboardItem) {
+	m.history = slices.Clone(history)
 	m.sortHistory()
 
 	// Ограничение размера истории
 	if len(m.history) > m.maxHistorySize {
 		m.history = m.history[:m.maxHistorySize]
 	}
+
+	m.notifyChange(types.Change{Op: types.OpReplace, Items: slices.Clone(m.history)})
 }
 
 func getPreview(content string) string {
@@ -168,3 +761,56 @@ func getPreview(content string) string {
 	}
 	return content[:32] + "..."
 }
+
+// Import добавляет в историю элементы из экспорта; с replace история
+// заменяется ими целиком. Возвращает число импортированных элементов.
+func (m *Manager) Import(items []types.ClipboardItem, replace bool) int {
+	m.acquire()
+	defer m.release()
+
+	imported := make([]types.ClipboardItem, 0, len(items))
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		if item.Content == "" && item.BlobHash == "" {
+			continue
+		}
+		if item.BlobHash == "" {
+			if item.Kind == "" {
+				item.Kind = DetectKind(item.Content)
+			}
+			item.Preview = getPreview(item.Content)
+			if m.blobs != nil && m.blobThreshold > 0 && len(item.Content) > m.blobThreshold {
+				hash, err := m.blobs.Put([]byte(item.Content))
+				if err != nil {
+					logger.Warn("failed to store blob, keeping the item inline", "err", err)
+				} else {
+					item.Size = len(item.Content)
+					item.Content = ""
+					item.BlobHash = hash
+				}
+			}
+		}
+		if item.Timestamp.IsZero() {
+			item.Timestamp = time.Now()
+		}
+		if seen[item.ID()] {
+			continue
+		}
+		seen[item.ID()] = true
+		imported = append(imported, item)
+	}
+
+	history := imported
+	if !replace {
+		history = append([]types.ClipboardItem{}, imported...)
+		for _, item := range m.history {
+			if !seen[item.ID()] {
+				history = append(history, item)
+			}
+		}
+	}
+
+	m.replaceHistory(history)
+	return len(imported)
+}
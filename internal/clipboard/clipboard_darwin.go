@@ -22,3 +22,14 @@ func SetClipboard(content string) error {
 	cmd.Stdin = strings.NewReader(content)
 	return cmd.Run()
 }
+
+// HasSensitiveHint проверяет, пометил ли источник содержимое буфера обмена
+// как конфиденциальное (тип org.nspasteboard.ConcealedType)
+func HasSensitiveHint() bool {
+	cmd := exec.Command("osascript", "-e", "clipboard info")
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(output), "org.nspasteboard.ConcealedType")
+}
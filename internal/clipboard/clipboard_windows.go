@@ -16,6 +16,7 @@ var (
 	getClipboardData           = user32.NewProc("GetClipboardData")
 	setClipboardData           = user32.NewProc("SetClipboardData")
 	isClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	registerClipboardFormat    = user32.NewProc("RegisterClipboardFormatW")
 
 	kernel32     = syscall.NewLazyDLL("kernel32.dll")
 	globalAlloc  = kernel32.NewProc("GlobalAlloc")
@@ -92,3 +93,20 @@ func SetClipboard(text string) error {
 	setClipboardData.Call(cfUnicodeText, h)
 	return nil
 }
+
+// HasSensitiveHint проверяет, пометил ли источник содержимое буфера обмена
+// как исключённое из истории (формат ExcludeClipboardContentFromMonitorProcessing)
+func HasSensitiveHint() bool {
+	name, err := syscall.UTF16PtrFromString("ExcludeClipboardContentFromMonitorProcessing")
+	if err != nil {
+		return false
+	}
+
+	format, _, _ := registerClipboardFormat.Call(uintptr(unsafe.Pointer(name)))
+	if format == 0 {
+		return false
+	}
+
+	available, _, _ := isClipboardFormatAvailable.Call(format)
+	return available != 0
+}
@@ -39,3 +39,20 @@ func SetClipboard(content string) error {
 	cmd.Stdin = strings.NewReader(content)
 	return cmd.Run()
 }
+
+// HasSensitiveHint проверяет, пометил ли источник содержимое буфера обмена
+// как конфиденциальное (например, менеджеры паролей KeePassXC и KWallet)
+func HasSensitiveHint() bool {
+	cmd := exec.Command("xclip", "-o", "-selection", "clipboard", "-t", "TARGETS")
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+
+	for _, target := range strings.Fields(string(output)) {
+		if target == "x-kde-passwordManagerHint" {
+			return true
+		}
+	}
+	return false
+}
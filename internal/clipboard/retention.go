@@ -0,0 +1,97 @@
+package clipboard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// RetentionPolicy ограничивает, что и как долго хранится в истории.
+// Нулевые значения отключают соответствующее ограничение. Закреплённые
+// и помеченные тегами элементы не удаляются.
+type RetentionPolicy struct {
+	MaxAge        time.Duration
+	MaxTotalBytes int64
+	MaxItemSize   int
+	KindTTL       map[string]time.Duration // Время жизни по виду содержимого
+}
+
+// SetRetention устанавливает политику хранения
+func (m *Manager) SetRetention(policy RetentionPolicy) {
+	m.acquire()
+	defer m.release()
+	m.retention = policy
+}
+
+// tooLarge сообщает, что содержимое превышает максимальный размер элемента
+func (m *Manager) tooLarge(content string) bool {
+	return m.retention.MaxItemSize > 0 && len(content) > m.retention.MaxItemSize
+}
+
+// ApplyRetention удаляет элементы, нарушающие политику хранения,
+// и возвращает их количество
+func (m *Manager) ApplyRetention(now time.Time) int {
+	m.acquire()
+	defer m.release()
+
+	policy := m.retention
+	var expired []string
+
+	for _, item := range m.history {
+		if item.Retained() {
+			continue
+		}
+
+		age := now.Sub(item.Timestamp)
+		switch {
+		case policy.MaxAge > 0 && age > policy.MaxAge:
+		case policy.KindTTL[item.Kind] > 0 && age > policy.KindTTL[item.Kind]:
+		case policy.MaxItemSize > 0 && item.ContentSize() > policy.MaxItemSize:
+		default:
+			continue
+		}
+		expired = append(expired, item.ID())
+	}
+
+	for _, id := range expired {
+		m.deleteItem(ByID(id))
+	}
+
+	return len(expired) + m.enforceTotalBytes(policy.MaxTotalBytes)
+}
+
+// enforceTotalBytes удаляет самые старые элементы, пока общий размер
+// истории превышает limit
+func (m *Manager) enforceTotalBytes(limit int64) int {
+	if limit <= 0 {
+		return 0
+	}
+
+	var total int64
+	candidates := make([]types.ClipboardItem, 0, len(m.history))
+	for _, item := range m.history {
+		total += int64(item.ContentSize())
+		if !item.Retained() {
+			candidates = append(candidates, item)
+		}
+	}
+	if total <= limit {
+		return 0
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Timestamp.Before(candidates[j].Timestamp)
+	})
+
+	removed := 0
+	for _, item := range candidates {
+		if total <= limit {
+			break
+		}
+		m.deleteItem(ByID(item.ID()))
+		total -= int64(item.ContentSize())
+		removed++
+	}
+	return removed
+}
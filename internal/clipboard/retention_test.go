@@ -0,0 +1,141 @@
+package clipboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+var retentionNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func retentionItem(content, kind string, age time.Duration) types.ClipboardItem {
+	return types.ClipboardItem{
+		Content:   content,
+		Preview:   content,
+		Kind:      kind,
+		Timestamp: retentionNow.Add(-age),
+	}
+}
+
+func historyContents(m *Manager) []string {
+	var result []string
+	for _, item := range m.GetHistory() {
+		result = append(result, item.Content)
+	}
+	return result
+}
+
+func assertHistory(t *testing.T, m *Manager, want ...string) {
+	t.Helper()
+	if got := historyContents(m); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("history = %q, want %q", got, want)
+	}
+}
+
+func TestApplyRetentionMaxAge(t *testing.T) {
+	m := NewManager([]types.ClipboardItem{
+		retentionItem("fresh", types.KindText, time.Hour),
+		retentionItem("old", types.KindText, 48*time.Hour),
+	}, 10, nil)
+	m.SetRetention(RetentionPolicy{MaxAge: 24 * time.Hour})
+
+	if removed := m.ApplyRetention(retentionNow); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	assertHistory(t, m, "fresh")
+}
+
+func TestApplyRetentionKindTTL(t *testing.T) {
+	m := NewManager([]types.ClipboardItem{
+		retentionItem("text", types.KindText, time.Hour),
+		retentionItem("hunter2", types.KindSecret, time.Hour),
+		retentionItem("recent secret", types.KindSecret, time.Minute),
+	}, 10, nil)
+	m.SetRetention(RetentionPolicy{KindTTL: map[string]time.Duration{types.KindSecret: 10 * time.Minute}})
+
+	if removed := m.ApplyRetention(retentionNow); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	assertHistory(t, m, "text", "recent secret")
+}
+
+func TestApplyRetentionMaxItemSize(t *testing.T) {
+	m := NewManager([]types.ClipboardItem{
+		retentionItem("small", types.KindText, time.Minute),
+		retentionItem(strings.Repeat("x", 100), types.KindText, time.Minute),
+	}, 10, nil)
+	m.SetRetention(RetentionPolicy{MaxItemSize: 50})
+
+	if removed := m.ApplyRetention(retentionNow); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	assertHistory(t, m, "small")
+}
+
+func TestApplyRetentionMaxTotalBytes(t *testing.T) {
+	m := NewManager([]types.ClipboardItem{
+		retentionItem("newest", types.KindText, time.Minute),
+		retentionItem("middle", types.KindText, time.Hour),
+		retentionItem("oldest", types.KindText, 2*time.Hour),
+	}, 10, nil)
+	// Три элемента по 6 байт: остаются только самые новые в пределах 12 байт
+	m.SetRetention(RetentionPolicy{MaxTotalBytes: 12})
+
+	if removed := m.ApplyRetention(retentionNow); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	assertHistory(t, m, "newest", "middle")
+}
+
+func TestApplyRetentionKeepsRetainedItems(t *testing.T) {
+	pinned := retentionItem("pinned", types.KindText, 48*time.Hour)
+	pinned.Pinned = true
+	tagged := retentionItem("tagged", types.KindSecret, 48*time.Hour)
+	tagged.Tags = []string{"keep"}
+
+	m := NewManager([]types.ClipboardItem{
+		pinned,
+		tagged,
+		retentionItem("old", types.KindText, 48*time.Hour),
+	}, 10, nil)
+	m.SetRetention(RetentionPolicy{
+		MaxAge:        time.Hour,
+		MaxItemSize:   1,
+		MaxTotalBytes: 1,
+		KindTTL:       map[string]time.Duration{types.KindSecret: time.Minute},
+	})
+
+	if removed := m.ApplyRetention(retentionNow); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	assertHistory(t, m, "pinned", "tagged")
+}
+
+func TestApplyRetentionNotifiesDeletes(t *testing.T) {
+	m := NewManager([]types.ClipboardItem{
+		retentionItem("old", types.KindText, 48*time.Hour),
+	}, 10, nil)
+	m.SetRetention(RetentionPolicy{MaxAge: time.Hour})
+
+	var changes []types.Change
+	m.SetChangeCallback(func(change types.Change) {
+		changes = append(changes, change)
+	})
+	m.ApplyRetention(retentionNow)
+
+	if len(changes) != 1 || changes[0].Op != types.OpDelete || changes[0].Content != "old" {
+		t.Fatalf("changes = %+v, want a single delete of %q", changes, "old")
+	}
+}
+
+func TestApplyRetentionZeroPolicy(t *testing.T) {
+	m := NewManager([]types.ClipboardItem{
+		retentionItem("old", types.KindText, 10000*time.Hour),
+	}, 10, nil)
+
+	if removed := m.ApplyRetention(retentionNow); removed != 0 {
+		t.Fatalf("removed = %d with an empty policy, want 0", removed)
+	}
+}
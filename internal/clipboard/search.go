@@ -0,0 +1,53 @@
+package clipboard
+
+import (
+	"strings"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// Search возвращает элементы истории, содержащие query без учёта регистра
+func (m *Manager) Search(query string) []types.ClipboardItem {
+	query = strings.ToLower(query)
+	var found []types.ClipboardItem
+
+	for _, item := range m.GetHistory() {
+		if m.Matches(item, query) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+// Matches сообщает, содержит ли элемент query; query должен быть в нижнем
+// регистре. Содержимое блоба загружается, только если query нет в превью
+// и тегах.
+func (m *Manager) Matches(item types.ClipboardItem, query string) bool {
+	if matches(item, query) {
+		return true
+	}
+	if item.BlobHash == "" {
+		return false
+	}
+
+	content, err := m.ItemContent(item)
+	if err != nil {
+		logger.Warn("failed to load blob for search", "hash", item.BlobHash, "err", err)
+		return false
+	}
+	return strings.Contains(strings.ToLower(content), query)
+}
+
+// matches ищет query в содержимом, превью и тегах элемента без загрузки блоба
+func matches(item types.ClipboardItem, query string) bool {
+	if strings.Contains(strings.ToLower(item.Content), query) ||
+		strings.Contains(strings.ToLower(item.Preview), query) {
+		return true
+	}
+	for _, tag := range item.Tags {
+		if strings.Contains(strings.ToLower(tag), query) {
+			return true
+		}
+	}
+	return false
+}
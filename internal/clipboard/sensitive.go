@@ -0,0 +1,86 @@
+package clipboard
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// Встроенные шаблоны, по которым содержимое считается секретом
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`^eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+$`), // JWT
+	regexp.MustCompile(`^(AKIA|ASIA)[0-9A-Z]{16}$`),                              // AWS access key
+	regexp.MustCompile(`^gh[pousr]_[A-Za-z0-9]{36,}$`),                           // GitHub token
+	regexp.MustCompile(`^xox[abprs]-[A-Za-z0-9-]{10,}$`),                         // Slack token
+}
+
+// SensitivePolicy определяет, какие элементы считаются конфиденциальными
+// и через сколько очищать системный буфер обмена после их копирования
+type SensitivePolicy struct {
+	Rules          []*regexp.Regexp
+	Kinds          []string
+	AutoClearDelay time.Duration
+}
+
+// NewSensitivePolicy компилирует пользовательские правила из конфигурации
+func NewSensitivePolicy(patterns []string, kinds []string, autoClearDelay time.Duration) (*SensitivePolicy, error) {
+	policy := &SensitivePolicy{
+		Kinds:          kinds,
+		AutoClearDelay: autoClearDelay,
+	}
+
+	for _, pattern := range patterns {
+		rule, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sensitive pattern %q: %w", pattern, err)
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	return policy, nil
+}
+
+// IsSensitive проверяет содержимое по правилам и виду
+func (p *SensitivePolicy) IsSensitive(content, kind string) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, k := range p.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	for _, rule := range p.Rules {
+		if rule.MatchString(content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DetectKind определяет вид содержимого
+func DetectKind(content string) string {
+	trimmed := strings.TrimSpace(content)
+
+	for _, pattern := range secretPatterns {
+		if pattern.MatchString(trimmed) {
+			return types.KindSecret
+		}
+	}
+
+	if !strings.ContainsAny(trimmed, " \n\t") {
+		if u, err := url.Parse(trimmed); err == nil && u.Host != "" && (u.Scheme == "http" || u.Scheme == "https") {
+			return types.KindURL
+		}
+	}
+
+	return types.KindText
+}
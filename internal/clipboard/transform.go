@@ -0,0 +1,37 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Transform — преобразование содержимого элемента
+type Transform struct {
+	Name  string
+	Apply func(content string) (string, error)
+}
+
+// Transforms возвращает доступные преобразования в порядке показа
+func Transforms() []Transform {
+	return []Transform{
+		{Name: "trim", Apply: func(s string) (string, error) { return strings.TrimSpace(s), nil }},
+		{Name: "lower", Apply: func(s string) (string, error) { return strings.ToLower(s), nil }},
+		{Name: "upper", Apply: func(s string) (string, error) { return strings.ToUpper(s), nil }},
+		{Name: "single line", Apply: func(s string) (string, error) { return strings.Join(strings.Fields(s), " "), nil }},
+		{Name: "json pretty", Apply: func(s string) (string, error) {
+			var buf bytes.Buffer
+			err := json.Indent(&buf, []byte(s), "", "  ")
+			return buf.String(), err
+		}},
+		{Name: "base64 encode", Apply: func(s string) (string, error) { return base64.StdEncoding.EncodeToString([]byte(s)), nil }},
+		{Name: "base64 decode", Apply: func(s string) (string, error) {
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+			return string(data), err
+		}},
+		{Name: "url encode", Apply: func(s string) (string, error) { return url.QueryEscape(s), nil }},
+		{Name: "url decode", Apply: func(s string) (string, error) { return url.QueryUnescape(strings.TrimSpace(s)) }},
+	}
+}
@@ -10,9 +10,83 @@ import (
 
 type Config struct {
 	MaxItems      int           `yaml:"max_items"`
-	CheckInterval time.Duration `yaml:"check_interval_ms"`
+	CheckInterval time.Duration `yaml:"check_interval_ms"` // Число — миллисекунды, можно и с единицами: 1s
 	StoragePath   string        `yaml:"storage_path"`
-	DebugMode     bool          `yaml:"debug_mode"`
+	StorageEngine string        `yaml:"storage_engine"` // json, jsonl, gob или journal
+	BackupCount   int           `yaml:"backup_count"`
+	BlobThreshold int           `yaml:"blob_threshold"` // Байт; крупнее — в хранилище блобов, 0 — отключено
+	DebugMode     bool          `yaml:"debug_mode"`     // Подробный журнал всех подсистем
+
+	Logging LoggingConfig `yaml:"logging"`
+
+	// Конфиденциальные элементы: регулярные выражения, виды содержимого
+	// и задержка автоочистки системного буфера обмена (0 — не очищать)
+	SensitivePatterns []string      `yaml:"sensitive_patterns"`
+	SensitiveKinds    []string      `yaml:"sensitive_kinds"`
+	AutoClearDelay    time.Duration `yaml:"auto_clear_delay"`
+
+	// Шифрование истории на диске. Ключ берётся из файла ключа, из переменной
+	// окружения SMART_CLIPBOARD_PASSPHRASE или запрашивается при запуске.
+	Encryption        bool   `yaml:"encryption"`
+	EncryptionKeyFile string `yaml:"encryption_key_file"`
+
+	// Автоблокировка истории после бездействия (0 — отключена).
+	// Работает только при шифровании с паролем. Снимается из трея или командой unlock.
+	IdleLockTimeout time.Duration `yaml:"idle_lock_timeout"`
+
+	// Действия при блокировке экрана: pause, lock, clear_clipboard, stop_sync
+	ScreenLockActions []string `yaml:"screen_lock_actions"`
+
+	Retention RetentionConfig `yaml:"retention"`
+
+	// Веб-интерфейс и HTTP API (/api/v1); ссылку с токеном доступа
+	// выводит smart-clipboard web, сам токен — smart-clipboard web -token
+	Web WebConfig `yaml:"web"`
+
+	// Сервис org.smartclipboard.Daemon на сессионной шине D-Bus (только Linux)
+	DBus DBusConfig `yaml:"dbus"`
+
+	// Синхронизация истории с другими компьютерами в локальной сети
+	Sync SyncConfig `yaml:"sync"`
+}
+
+// SyncConfig — настройки синхронизации
+type SyncConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// DBusConfig — настройки сервиса D-Bus
+type DBusConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Меню для метода ShowPicker, например "rofi -dmenu". Если не задано,
+	// используется первое найденное из rofi, wofi, fuzzel, bemenu и dmenu.
+	PickerCommand string `yaml:"picker_command"`
+}
+
+// LoggingConfig — уровни и файл журнала
+type LoggingConfig struct {
+	Level string `yaml:"level"` // debug, info, warn или error
+	// Уровни подсистем: main, clipboard, storage, sync, ipc, web, dbus,
+	// session, maintenance, tray, lifecycle. Например: sync: warn
+	Levels   map[string]string `yaml:"levels"`
+	File     string            `yaml:"file"`      // Пусто — файл в каталоге состояния, "off" — без файла
+	MaxSize  int64             `yaml:"max_size"`  // Байт до ротации файла
+	MaxFiles int               `yaml:"max_files"` // Сколько старых файлов хранить
+}
+
+// WebConfig — настройки веб-интерфейса и HTTP API
+type WebConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"` // Только адрес обратной петли, например 127.0.0.1:7390
+}
+
+// RetentionConfig — политика хранения истории. Нулевые значения отключают
+// ограничение; закреплённые и помеченные тегами элементы не удаляются.
+type RetentionConfig struct {
+	MaxAge        time.Duration            `yaml:"max_age"`
+	MaxTotalBytes int64                    `yaml:"max_total_bytes"`
+	MaxItemSize   int                      `yaml:"max_item_size"`
+	KindTTL       map[string]time.Duration `yaml:"kind_ttl"` // Например: url: 720h, secret: 1m
 }
 
 func DefaultConfig() *Config {
@@ -20,33 +94,30 @@ func DefaultConfig() *Config {
 		MaxItems:      40,
 		CheckInterval: 1000 * time.Millisecond,
 		StoragePath:   getDefaultStoragePath(),
+		StorageEngine: "journal",
+		BackupCount:   3,
+		BlobThreshold: 64 * 1024,
 		DebugMode:     false,
-	}
-}
 
-func LoadConfig() (*Config, error) {
-	configPath := getConfigPath()
+		SensitiveKinds: []string{"secret"},
+		AutoClearDelay: 30 * time.Second,
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return DefaultConfig(), nil
-	}
+		IdleLockTimeout: 15 * time.Minute,
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
+		Logging: LoggingConfig{
+			Level:    "info",
+			MaxSize:  10 * 1024 * 1024,
+			MaxFiles: 3,
+		},
 
-	var cfg Config
-	err = yaml.Unmarshal(data, &cfg)
-	if err != nil {
-		return nil, err
+		Web:  WebConfig{Listen: "127.0.0.1:7390"},
+		DBus: DBusConfig{Enabled: true},
+		Sync: SyncConfig{Enabled: true},
 	}
-
-	return &cfg, nil
 }
 
 func SaveConfig(cfg *Config) error {
-	configPath := getConfigPath()
+	configPath := Path()
 
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -59,7 +130,12 @@ func SaveConfig(cfg *Config) error {
 	return os.WriteFile(configPath, data, 0644)
 }
 
-func getConfigPath() string {
+// Path возвращает путь к файлу конфигурации; другой файл можно задать
+// переменной окружения SMART_CLIPBOARD_CONFIG
+func Path() string {
+	if path := os.Getenv(pathEnv); path != "" {
+		return path
+	}
 	configDir, _ := os.UserConfigDir()
 	return filepath.Join(configDir, "smart-clipboard", "config.yaml")
 }
@@ -68,3 +144,9 @@ func getDefaultStoragePath() string {
 	configDir, _ := os.UserConfigDir()
 	return filepath.Join(configDir, "smart-clipboard", "history.json")
 }
+
+// WebTokenPath возвращает путь к файлу с токеном доступа к веб-интерфейсу
+func WebTokenPath() string {
+	configDir, _ := os.UserConfigDir()
+	return filepath.Join(configDir, "smart-clipboard", "web-token")
+}
@@ -0,0 +1,40 @@
+package config
+
+import "sync/atomic"
+
+// Live — действующая конфигурация работающего демона. Опубликованная
+// конфигурация не меняется: изменения публикуются новой копией через Store,
+// поэтому Load можно вызывать из любой горутины.
+type Live struct {
+	current atomic.Pointer[Config]
+}
+
+// NewLive публикует начальную конфигурацию
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.Store(cfg)
+	return l
+}
+
+// Load возвращает действующую конфигурацию. Менять её нельзя.
+func (l *Live) Load() *Config {
+	return l.current.Load()
+}
+
+// Store публикует новую конфигурацию
+func (l *Live) Store(cfg *Config) {
+	l.current.Store(cfg)
+}
+
+// Update публикует копию действующей конфигурации, изменённую change,
+// и возвращает её
+func (l *Live) Update(change func(cfg *Config)) *Config {
+	for {
+		current := l.current.Load()
+		next := *current
+		change(&next)
+		if l.current.CompareAndSwap(current, &next) {
+			return &next
+		}
+	}
+}
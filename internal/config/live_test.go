@@ -0,0 +1,40 @@
+package config
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLiveUpdateKeepsPublishedConfig(t *testing.T) {
+	live := NewLive(DefaultConfig())
+	before := live.Load()
+
+	next := live.Update(func(cfg *Config) { cfg.MaxItems = 99 })
+	if before.MaxItems == 99 {
+		t.Fatal("Update changed the published config in place")
+	}
+	if next.MaxItems != 99 || live.Load() != next {
+		t.Fatal("Update did not publish the new config")
+	}
+}
+
+func TestLiveConcurrentUpdates(t *testing.T) {
+	live := NewLive(&Config{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				live.Update(func(cfg *Config) { cfg.MaxItems++ })
+				_ = live.Load().MaxItems
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := live.Load().MaxItems; got != 800 {
+		t.Fatalf("MaxItems = %d after 800 updates", got)
+	}
+}
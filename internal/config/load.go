@@ -0,0 +1,368 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix — префикс переменных окружения, переопределяющих настройки.
+// Имя переменной — путь ключа в верхнем регистре: SMART_CLIPBOARD_MAX_ITEMS,
+// SMART_CLIPBOARD_WEB_LISTEN, SMART_CLIPBOARD_LOGGING_LEVEL.
+const envPrefix = "SMART_CLIPBOARD_"
+
+// pathEnv задаёт другой файл конфигурации
+const pathEnv = "SMART_CLIPBOARD_CONFIG"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var (
+	overridesMu sync.Mutex
+	overrides   []override
+)
+
+// override — значение настройки из командной строки
+type override struct {
+	key   string
+	value string
+}
+
+// key — путь настройки через точку и тип поля Config
+type key struct {
+	path string
+	typ  reflect.Type
+}
+
+// Override задаёт значение настройки поверх файла и окружения, например из
+// флага командной строки. key — путь через точку: max_items, web.listen.
+// Значение действует при каждой следующей загрузке конфигурации.
+func Override(key, value string) error {
+	if _, ok := lookupKey(key); !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	overrides = append(overrides, override{key: key, value: value})
+	return nil
+}
+
+// SaveKey записывает в файл конфигурации (см. Path) одно значение, не трогая
+// остальное содержимое файла. Значения по умолчанию, из окружения и из
+// Override в файл не попадают. value записывается как в Override.
+func SaveKey(key, value string) error {
+	k, ok := lookupKey(key)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	node, err := valueNode(k.typ, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	path := Path()
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	setNode(doc.Content[0], strings.Split(key, "."), node)
+
+	data, err = yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+
+	// Файл с новым значением должен загружаться сам по себе
+	var check yaml.Node
+	if err := yaml.Unmarshal(data, &check); err != nil {
+		return err
+	}
+	cfg := DefaultConfig()
+	if err := decode(check.Content[0], cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadConfig загружает конфигурацию из файла по умолчанию (см. Path)
+func LoadConfig() (*Config, error) {
+	return LoadFile(Path())
+}
+
+// LoadFile собирает конфигурацию по слоям: значения по умолчанию, файл path,
+// переменные окружения SMART_CLIPBOARD_*, затем значения из Override.
+// Ключи, которых нет в файле, сохраняют значения по умолчанию. Результат
+// проверяется, ошибки перечисляются все сразу.
+func LoadFile(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(doc.Content) > 0 {
+			if err := decode(doc.Content[0], cfg); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	env, err := envNode()
+	if err != nil {
+		return nil, fmt.Errorf("environment: %w", err)
+	}
+	if err := decode(env, cfg); err != nil {
+		return nil, fmt.Errorf("environment: %w", err)
+	}
+
+	flags, err := overridesNode()
+	if err != nil {
+		return nil, fmt.Errorf("command line: %w", err)
+	}
+	if err := decode(flags, cfg); err != nil {
+		return nil, fmt.Errorf("command line: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decode накладывает отображение YAML на cfg: заданные ключи заменяют
+// текущие значения, остальные не меняются
+func decode(node *yaml.Node, cfg *Config) error {
+	if err := checkNode(node, reflect.TypeOf(*cfg), ""); err != nil {
+		return err
+	}
+	return node.Decode(cfg)
+}
+
+// checkNode ищет неизвестные ключи и приводит длительности без единиц
+func checkNode(node *yaml.Node, t reflect.Type, path string) error {
+	if node.Kind != yaml.MappingNode {
+		return nil // Несовпадение типов сообщит Decode
+	}
+
+	var errs []error
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, value := node.Content[i], node.Content[i+1]
+		name := joinKey(path, keyNode.Value)
+
+		field, ok := fieldByKey(t, keyNode.Value)
+		if !ok {
+			errs = append(errs, nodeError(keyNode, name, "unknown key"))
+			continue
+		}
+
+		switch {
+		case field.Type == durationType:
+			errs = append(errs, fixDuration(value, name))
+		case field.Type.Kind() == reflect.Map && field.Type.Elem() == durationType && value.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				errs = append(errs, fixDuration(value.Content[j+1], joinKey(name, value.Content[j].Value)))
+			}
+		case field.Type.Kind() == reflect.Struct:
+			errs = append(errs, checkNode(value, field.Type, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fixDuration разбирает длительность, записанную числом. В ключах с
+// суффиксом _ms это миллисекунды, в остальных число без единиц — ошибка:
+// YAML прочитал бы 30 как 30 наносекунд. Ноль (отключено) допустим везде.
+func fixDuration(node *yaml.Node, name string) error {
+	if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!int" {
+		return nil
+	}
+	if node.Value == "0" {
+		node.Value = "0s"
+		node.Tag = "!!str"
+		return nil
+	}
+	if strings.HasSuffix(name, "_ms") {
+		node.Value += "ms"
+		node.Tag = "!!str"
+		return nil
+	}
+	return nodeError(node, name, "duration needs a unit, for example %ss or %sm", node.Value, node.Value)
+}
+
+// nodeError добавляет к ошибке номер строки, если значение взято из файла
+func nodeError(node *yaml.Node, name, format string, args ...any) error {
+	msg := name + ": " + fmt.Sprintf(format, args...)
+	if node.Line > 0 {
+		return fmt.Errorf("line %d: %s", node.Line, msg)
+	}
+	return errors.New(msg)
+}
+
+// envNode собирает отображение из переменных окружения SMART_CLIPBOARD_*
+func envNode() (*yaml.Node, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, k := range keys(reflect.TypeOf(Config{}), "") {
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(k.path, ".", "_"))
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		value, err := valueNode(k.typ, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		setNode(root, strings.Split(k.path, "."), value)
+	}
+	return root, nil
+}
+
+// overridesNode собирает отображение из значений Override
+func overridesNode() (*yaml.Node, error) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, o := range overrides {
+		k, _ := lookupKey(o.key)
+		value, err := valueNode(k.typ, o.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", o.key, err)
+		}
+		setNode(root, strings.Split(o.key, "."), value)
+	}
+	return root, nil
+}
+
+// valueNode разбирает значение из окружения или командной строки. Строки
+// берутся как есть, списки строк можно перечислить через запятую, остальное
+// записывается как в YAML: 30s, true, [a, b], {sync: debug}.
+func valueNode(t reflect.Type, raw string) (*yaml.Node, error) {
+	if t.Kind() == reflect.String {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: raw}, nil
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		list := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return list, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}, nil
+	}
+	return doc.Content[0], nil
+}
+
+// setNode кладёт значение в дерево по пути, создавая вложенные отображения
+func setNode(root *yaml.Node, path []string, value *yaml.Node) {
+	node := root
+	for _, name := range path[:len(path)-1] {
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				next = node.Content[i+1]
+			}
+		}
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, next)
+		}
+		node = next
+	}
+	name := path[len(path)-1]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+}
+
+// keys перечисляет все настройки в порядке полей. Вложенные структуры
+// раскрываются, отображения и списки считаются одним значением.
+func keys(t reflect.Type, prefix string) []key {
+	var result []key
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+		path := joinKey(prefix, name)
+		if field.Type.Kind() == reflect.Struct {
+			result = append(result, keys(field.Type, path)...)
+		} else {
+			result = append(result, key{path: path, typ: field.Type})
+		}
+	}
+	return result
+}
+
+func lookupKey(path string) (key, bool) {
+	for _, k := range keys(reflect.TypeOf(Config{}), "") {
+		if k.path == path {
+			return k, true
+		}
+	}
+	return key{}, false
+}
+
+func fieldByKey(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); yamlName(field) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig записывает файл конфигурации во временный каталог теста
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// resetOverrides сбрасывает значения Override в конце теста
+func resetOverrides(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		overridesMu.Lock()
+		defer overridesMu.Unlock()
+		overrides = nil
+	})
+}
+
+func mustLoadFile(t *testing.T, path string) *Config {
+	t.Helper()
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	return cfg
+}
+
+func TestLoadFileMissingUsesDefaults(t *testing.T) {
+	cfg := mustLoadFile(t, filepath.Join(t.TempDir(), "missing.yaml"))
+	defaults := DefaultConfig()
+	if cfg.MaxItems != defaults.MaxItems || cfg.StorageEngine != defaults.StorageEngine || cfg.Web.Listen != defaults.Web.Listen {
+		t.Fatalf("cfg = %+v, want defaults", cfg)
+	}
+}
+
+func TestLoadFileKeepsDefaultsForMissingKeys(t *testing.T) {
+	cfg := mustLoadFile(t, writeConfig(t, "max_items: 7\nlogging:\n  level: debug\n"))
+
+	if cfg.MaxItems != 7 || cfg.Logging.Level != "debug" {
+		t.Fatalf("file values not applied: %+v", cfg)
+	}
+	// Соседние ключи вложенной секции остаются по умолчанию
+	if cfg.Logging.MaxFiles != DefaultConfig().Logging.MaxFiles {
+		t.Fatalf("logging.max_files = %d, want the default", cfg.Logging.MaxFiles)
+	}
+	if !cfg.DBus.Enabled {
+		t.Fatal("dbus.enabled lost its default")
+	}
+}
+
+func TestLoadFileLayering(t *testing.T) {
+	resetOverrides(t)
+	path := writeConfig(t, "max_items: 10\nstorage_engine: gob\nweb:\n  listen: 127.0.0.1:1000\n")
+
+	t.Setenv("SMART_CLIPBOARD_MAX_ITEMS", "20")
+	t.Setenv("SMART_CLIPBOARD_WEB_LISTEN", "127.0.0.1:2000")
+	t.Setenv("SMART_CLIPBOARD_SENSITIVE_KINDS", "secret, url")
+	if err := Override("max_items", "30"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := mustLoadFile(t, path)
+	if cfg.MaxItems != 30 {
+		t.Fatalf("max_items = %d, want the command line value 30", cfg.MaxItems)
+	}
+	if cfg.Web.Listen != "127.0.0.1:2000" {
+		t.Fatalf("web.listen = %q, want the environment value", cfg.Web.Listen)
+	}
+	if cfg.StorageEngine != "gob" {
+		t.Fatalf("storage_engine = %q, want the file value", cfg.StorageEngine)
+	}
+	if strings.Join(cfg.SensitiveKinds, ",") != "secret,url" {
+		t.Fatalf("sensitive_kinds = %q, want [secret url]", cfg.SensitiveKinds)
+	}
+}
+
+func TestOverrideUnknownKey(t *testing.T) {
+	resetOverrides(t)
+	if err := Override("no_such_key", "1"); err == nil {
+		t.Fatal("Override accepted an unknown key")
+	}
+	if err := Override("web", "x"); err == nil {
+		t.Fatal("Override accepted a section instead of a key")
+	}
+}
+
+func TestLoadFileDurations(t *testing.T) {
+	cfg := mustLoadFile(t, writeConfig(t, `
+check_interval_ms: 250
+auto_clear_delay: 45s
+idle_lock_timeout: 0
+retention:
+  kind_ttl:
+    secret: 1m
+`))
+
+	if cfg.CheckInterval != 250*time.Millisecond {
+		t.Fatalf("check_interval_ms = %v, want 250ms", cfg.CheckInterval)
+	}
+	if cfg.AutoClearDelay != 45*time.Second || cfg.IdleLockTimeout != 0 {
+		t.Fatalf("durations = %v, %v", cfg.AutoClearDelay, cfg.IdleLockTimeout)
+	}
+	if cfg.Retention.KindTTL["secret"] != time.Minute {
+		t.Fatalf("retention.kind_ttl.secret = %v, want 1m", cfg.Retention.KindTTL["secret"])
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"unknown key", "max_item: 5\n", []string{"line 1", "max_item: unknown key"}},
+		{"unknown nested key", "web:\n  port: 80\n", []string{"line 2", "web.port: unknown key"}},
+		{"duration without unit", "auto_clear_delay: 30\n", []string{"auto_clear_delay: duration needs a unit"}},
+		{"kind ttl without unit", "retention:\n  kind_ttl:\n    url: 5\n", []string{"retention.kind_ttl.url"}},
+		{"wrong type", "max_items: many\n", []string{"line 1", "many"}},
+		{"several errors", "max_item: 5\nweb:\n  port: 80\n", []string{"max_item", "web.port"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeConfig(t, tt.content))
+			if err == nil {
+				t.Fatal("LoadFile succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("err = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFileInvalidEnvironment(t *testing.T) {
+	t.Setenv("SMART_CLIPBOARD_MAX_ITEMS", "[1")
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "SMART_CLIPBOARD_MAX_ITEMS") {
+		t.Fatalf("err = %v, want it to name the variable", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("defaults are invalid: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		change func(cfg *Config)
+	}{
+		{"max_items", func(cfg *Config) { cfg.MaxItems = 0 }},
+		{"max_items", func(cfg *Config) { cfg.MaxItems = MaxItemsLimit + 1 }},
+		{"check_interval_ms", func(cfg *Config) { cfg.CheckInterval = time.Millisecond }},
+		{"storage_path", func(cfg *Config) { cfg.StoragePath = "" }},
+		{"storage_engine", func(cfg *Config) { cfg.StorageEngine = "xml" }},
+		{"sensitive_patterns", func(cfg *Config) { cfg.SensitivePatterns = []string{"("} }},
+		{"screen_lock_actions", func(cfg *Config) { cfg.ScreenLockActions = []string{"explode"} }},
+		{"retention.kind_ttl.url", func(cfg *Config) { cfg.Retention.KindTTL = map[string]time.Duration{"url": -time.Second} }},
+		{"logging.level", func(cfg *Config) { cfg.Logging.Level = "loud" }},
+		{"logging.levels.sync", func(cfg *Config) { cfg.Logging.Levels = map[string]string{"sync": "loud"} }},
+		{"web.listen", func(cfg *Config) { cfg.Web.Enabled = true; cfg.Web.Listen = "localhost" }},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.change(cfg)
+		err := cfg.Validate()
+		if err == nil || !strings.HasPrefix(err.Error(), tt.key+":") {
+			t.Errorf("%s: err = %v, want an error for the key", tt.key, err)
+		}
+	}
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxItems = 0
+	cfg.BackupCount = -1
+	cfg.Logging.Level = "loud"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate succeeded")
+	}
+	for _, key := range []string{"max_items", "backup_count", "logging.level"} {
+		if !strings.Contains(err.Error(), key+":") {
+			t.Fatalf("err = %q, want it to mention %s", err, key)
+		}
+	}
+}
+
+func TestSaveConfigRoundTrip(t *testing.T) {
+	t.Setenv(pathEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	cfg := DefaultConfig()
+	cfg.MaxItems = 12
+	cfg.CheckInterval = 250 * time.Millisecond
+	cfg.Retention.KindTTL = map[string]time.Duration{"url": 720 * time.Hour}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := mustLoadFile(t, Path())
+	if loaded.MaxItems != 12 || loaded.CheckInterval != 250*time.Millisecond || loaded.Retention.KindTTL["url"] != 720*time.Hour {
+		t.Fatalf("loaded = %+v, want the saved values", loaded)
+	}
+}
+
+func TestSaveKeyWritesOnlyTheFileLayer(t *testing.T) {
+	resetOverrides(t)
+	path := writeConfig(t, "# мой файл\nmax_items: 10\nlogging:\n  level: debug\n")
+	t.Setenv(pathEnv, path)
+	t.Setenv("SMART_CLIPBOARD_WEB_LISTEN", "127.0.0.1:2000")
+	if err := Override("storage_engine", "gob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveKey("max_items", "15"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveKey("logging.max_files", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"web", "storage_engine", "backup_count"} {
+		if strings.Contains(string(data), leaked) {
+			t.Fatalf("file = %q, want no %s from other layers", data, leaked)
+		}
+	}
+	if !strings.Contains(string(data), "# мой файл") {
+		t.Fatalf("file = %q, comment lost", data)
+	}
+
+	cfg := mustLoadFile(t, path)
+	if cfg.MaxItems != 15 || cfg.Logging.Level != "debug" || cfg.Logging.MaxFiles != 2 {
+		t.Fatalf("cfg = %+v, want the saved keys next to the old ones", cfg)
+	}
+}
+
+func TestSaveKeyRejectsInvalidValues(t *testing.T) {
+	path := writeConfig(t, "max_items: 10\n")
+	t.Setenv(pathEnv, path)
+
+	if err := SaveKey("no_such_key", "1"); err == nil {
+		t.Fatal("SaveKey accepted an unknown key")
+	}
+	if err := SaveKey("max_items", "-1"); err == nil {
+		t.Fatal("SaveKey accepted an invalid value")
+	}
+	if cfg := mustLoadFile(t, path); cfg.MaxItems != 10 {
+		t.Fatalf("max_items = %d, want the file left unchanged", cfg.MaxItems)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/storage"
+)
+
+// Допустимые границы настроек
+const (
+	MaxItemsLimit    = 1000
+	MinCheckInterval = 50 * time.Millisecond
+	MaxCheckInterval = time.Minute
+)
+
+// screenLockActions — действия, которые понимает пакет session
+var screenLockActions = []string{"pause", "lock", "clear_clipboard", "stop_sync"}
+
+// Validate проверяет значения настроек и возвращает все найденные ошибки
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(c.MaxItems >= 1 && c.MaxItems <= MaxItemsLimit, "max_items", "must be between 1 and %d, got %d", MaxItemsLimit, c.MaxItems)
+	check(c.CheckInterval >= MinCheckInterval && c.CheckInterval <= MaxCheckInterval, "check_interval_ms",
+		"must be between %s and %s, got %s", MinCheckInterval, MaxCheckInterval, c.CheckInterval)
+	check(c.StoragePath != "", "storage_path", "must not be empty")
+	check(c.StorageEngine == "" || slices.Contains(storage.Engines(), c.StorageEngine), "storage_engine",
+		"unknown engine %q, expected one of %v", c.StorageEngine, storage.Engines())
+	check(c.BackupCount >= 0, "backup_count", "must not be negative")
+	check(c.BlobThreshold >= 0, "blob_threshold", "must not be negative")
+
+	for _, pattern := range c.SensitivePatterns {
+		_, err := regexp.Compile(pattern)
+		check(err == nil, "sensitive_patterns", "invalid pattern %q: %v", pattern, err)
+	}
+	check(c.AutoClearDelay >= 0, "auto_clear_delay", "must not be negative")
+	check(c.IdleLockTimeout >= 0, "idle_lock_timeout", "must not be negative")
+	for _, action := range c.ScreenLockActions {
+		check(slices.Contains(screenLockActions, action), "screen_lock_actions",
+			"unknown action %q, expected one of %v", action, screenLockActions)
+	}
+
+	check(c.Retention.MaxAge >= 0, "retention.max_age", "must not be negative")
+	check(c.Retention.MaxTotalBytes >= 0, "retention.max_total_bytes", "must not be negative")
+	check(c.Retention.MaxItemSize >= 0, "retention.max_item_size", "must not be negative")
+	for kind, ttl := range c.Retention.KindTTL {
+		check(ttl > 0, "retention.kind_ttl."+kind, "must be positive")
+	}
+
+	check(validLevel(c.Logging.Level), "logging.level", "unknown level %q, expected debug, info, warn or error", c.Logging.Level)
+	for subsystem, level := range c.Logging.Levels {
+		check(validLevel(level), "logging.levels."+subsystem, "unknown level %q, expected debug, info, warn or error", level)
+	}
+	check(c.Logging.MaxSize >= 0, "logging.max_size", "must not be negative")
+	check(c.Logging.MaxFiles >= 0, "logging.max_files", "must not be negative")
+
+	if c.Web.Enabled || c.Web.Listen != "" {
+		check(validListen(c.Web.Listen), "web.listen", "expected host:port, got %q", c.Web.Listen)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validLevel(level string) bool {
+	if level == "" {
+		return true
+	}
+	var l slog.Level
+	return l.UnmarshalText([]byte(level)) == nil
+}
+
+func validListen(listen string) bool {
+	_, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 0 && n <= 65535
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchInterval — как часто проверяется файл конфигурации
+const WatchInterval = 2 * time.Second
+
+// Watch вызывает onChange, когда файл конфигурации изменился, появился или
+// был удалён. Файл опрашивается: так изменения видны на любой платформе и
+// после атомарной замены файла редактором. Возвращается при отмене ctx.
+func Watch(ctx context.Context, onChange func()) {
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+
+	last := fileState(Path())
+	pending := false
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		// Изменение применяется, когда файл перестаёт меняться: редактор
+		// мог ещё не дописать его
+		current := fileState(Path())
+		switch {
+		case current != last:
+			last = current
+			pending = true
+		case pending:
+			pending = false
+			onChange()
+		}
+	}
+}
+
+// state — признаки изменения файла
+type state struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func fileState(path string) state {
+	info, err := os.Stat(path)
+	if err != nil {
+		return state{}
+	}
+	return state{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
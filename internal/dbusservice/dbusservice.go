@@ -0,0 +1,34 @@
+// Package dbusservice публикует демон на сессионной шине D-Bus под именем
+// org.smartclipboard.Daemon, чтобы расширения рабочего стола, виджеты и
+// скрипты (gdbus, busctl, dbus-send) могли читать историю и управлять
+// демоном. Вызовы выполняются через API демона (см. пакет ipc). Служба
+// доступна только в Linux.
+//
+//	gdbus call --session --dest org.smartclipboard.Daemon \
+//	    --object-path /org/smartclipboard/Daemon \
+//	    --method org.smartclipboard.Daemon.GetHistory 10
+package dbusservice
+
+const (
+	// BusName — имя на шине, которым владеет демон
+	BusName = "org.smartclipboard.Daemon"
+	// ObjectPath — путь объекта демона
+	ObjectPath = "/org/smartclipboard/Daemon"
+	// Interface — интерфейс объекта демона
+	Interface = "org.smartclipboard.Daemon"
+
+	// Имена ошибок методов помимо стандартных org.freedesktop.DBus.Error.*
+	ErrorNotFound = Interface + ".Error.NotFound"
+	ErrorLocked   = Interface + ".Error.Locked"
+)
+
+// Сигналы объекта демона
+const (
+	// SignalItemAdded (id, preview, kind) — элемент добавлен в историю.
+	// Превью конфиденциального элемента скрыто.
+	SignalItemAdded = "ItemAdded"
+	// SignalItemUsed (id) — элемент снова помещён в буфер обмена
+	SignalItemUsed = "ItemUsed"
+	// SignalSyncPeerChanged (peer, connected) — найден узел синхронизации
+	SignalSyncPeerChanged = "SyncPeerChanged"
+)
@@ -0,0 +1,295 @@
+//go:build linux
+// +build linux
+
+package dbusservice
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+var logger = logging.For("dbus")
+
+// HistoryItem — элемент, который возвращает GetHistory, сигнатура D-Bus (sssbbx)
+type HistoryItem struct {
+	ID        string
+	Preview   string
+	Kind      string
+	Pinned    bool
+	Sensitive bool
+	Timestamp int64 // Время Unix в секундах
+}
+
+// Данные интроспекции описаны вручную, чтобы у аргументов были имена
+var methods = []introspect.Method{
+	{Name: "GetHistory", Args: []introspect.Arg{{Name: "limit", Type: "i", Direction: "in"}, {Name: "items", Type: "a(sssbbx)", Direction: "out"}}},
+	{Name: "Copy", Args: []introspect.Arg{{Name: "id", Type: "s", Direction: "in"}}},
+	{Name: "Add", Args: []introspect.Arg{{Name: "content", Type: "s", Direction: "in"}, {Name: "id", Type: "s", Direction: "out"}}},
+	{Name: "Delete", Args: []introspect.Arg{{Name: "id", Type: "s", Direction: "in"}}},
+	{Name: "Pause", Args: []introspect.Arg{{Name: "seconds", Type: "u", Direction: "in"}}},
+	{Name: "Resume"},
+	{Name: "ShowPicker"},
+}
+
+var signals = []introspect.Signal{
+	{Name: SignalItemAdded, Args: []introspect.Arg{{Name: "id", Type: "s"}, {Name: "preview", Type: "s"}, {Name: "kind", Type: "s"}}},
+	{Name: SignalItemUsed, Args: []introspect.Arg{{Name: "id", Type: "s"}}},
+	{Name: SignalSyncPeerChanged, Args: []introspect.Arg{{Name: "peer", Type: "s"}, {Name: "connected", Type: "b"}}},
+}
+
+// Service владеет именем на шине, обслуживает объект демона и превращает
+// изменения истории в сигналы
+type Service struct {
+	conn       *dbus.Conn
+	ownsConn   bool
+	api        *ipc.Server
+	showPicker func() error
+
+	mu     sync.Mutex
+	cancel func() // Отменяет текущую подписку на историю
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// Export подключается к сессионной шине текущего пользователя и публикует
+// на ней демон. showPicker вызывается методом ShowPicker и может быть nil.
+func Export(api *ipc.Server, showPicker func() error) (*Service, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("no session bus: %w", err)
+	}
+
+	s, err := NewService(conn, api, showPicker)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	s.ownsConn = true
+	return s, nil
+}
+
+// NewService публикует демон в подключении conn и занимает имя на шине.
+// Подключение к отдельному dbus-daemon позволяет проверять службу без
+// рабочего стола.
+func NewService(conn *dbus.Conn, api *ipc.Server, showPicker func() error) (*Service, error) {
+	s := &Service{
+		conn:       conn,
+		api:        api,
+		showPicker: showPicker,
+		cancel:     func() {},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	object := &daemonObject{service: s}
+	if err := conn.Export(object, ObjectPath, Interface); err != nil {
+		return nil, fmt.Errorf("failed to export %s: %w", ObjectPath, err)
+	}
+
+	node := &introspect.Node{
+		Name: ObjectPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{Name: Interface, Methods: methods, Signals: signals},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), ObjectPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		s.unexport()
+		return nil, fmt.Errorf("failed to export introspection data: %w", err)
+	}
+
+	reply, err := conn.RequestName(BusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		s.unexport()
+		return nil, fmt.Errorf("failed to request name %s: %w", BusName, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		s.unexport()
+		return nil, fmt.Errorf("D-Bus name %s is already taken", BusName)
+	}
+
+	go s.forward()
+	return s, nil
+}
+
+func (s *Service) unexport() {
+	s.conn.Export(nil, ObjectPath, Interface)
+	s.conn.Export(nil, ObjectPath, "org.freedesktop.DBus.Introspectable")
+}
+
+// forward отправляет сигналы об изменениях истории. Подписка, отключённая
+// за отставание, возобновляется: сигналы — лишь уведомления, и пропустить
+// часть лучше, чем перестать их отправлять.
+func (s *Service) forward() {
+	defer close(s.done)
+
+	for {
+		events, cancel := s.api.Subscribe()
+		s.mu.Lock()
+		s.cancel = cancel
+		s.mu.Unlock()
+
+		select {
+		case <-s.stop:
+			cancel()
+			return
+		default:
+		}
+
+		for event := range events {
+			s.emitEvent(event)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		default:
+			logger.Warn("fell behind history changes, resubscribing")
+		}
+	}
+}
+
+func (s *Service) emitEvent(event ipc.Event) {
+	switch event.Op {
+	case types.OpAdd:
+		if event.Item != nil {
+			s.emit(SignalItemAdded, event.ID, event.Item.Preview, event.Item.Kind)
+		}
+	case types.OpUse:
+		s.emit(SignalItemUsed, event.ID)
+	}
+}
+
+func (s *Service) emit(name string, args ...interface{}) {
+	if err := s.conn.Emit(ObjectPath, Interface+"."+name, args...); err != nil {
+		logger.Warn("failed to emit signal", "signal", name, "err", err)
+	}
+}
+
+// PeerChanged отправляет SyncPeerChanged; предназначен для callback узлов
+// менеджера синхронизации
+func (s *Service) PeerChanged(peer string, connected bool) {
+	s.emit(SignalSyncPeerChanged, peer, connected)
+}
+
+// Close освобождает имя на шине, прекращает отправку сигналов и закрывает
+// подключение, если его открыл Export
+func (s *Service) Close() error {
+	close(s.stop)
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	cancel()
+	<-s.done
+
+	s.unexport()
+	if _, err := s.conn.ReleaseName(BusName); err != nil {
+		logger.Warn("failed to release bus name", "name", BusName, "err", err)
+	}
+	if s.ownsConn {
+		return s.conn.Close()
+	}
+	return nil
+}
+
+// daemonObject содержит методы D-Bus демона: каждый экспортируемый метод
+// становится методом интерфейса
+type daemonObject struct {
+	service *Service
+}
+
+// GetHistory возвращает до limit элементов, новые первыми; 0 — все элементы
+func (o *daemonObject) GetHistory(limit int32) ([]HistoryItem, *dbus.Error) {
+	if limit < 0 {
+		return nil, dbus.NewError("org.freedesktop.DBus.Error.InvalidArgs", []interface{}{"limit must not be negative"})
+	}
+
+	var items []ipc.Item
+	if err := o.service.api.Call(ipc.MethodList, ipc.ListParams{Limit: int(limit)}, &items); err != nil {
+		return nil, busError(err)
+	}
+
+	history := make([]HistoryItem, 0, len(items))
+	for _, item := range items {
+		history = append(history, HistoryItem{
+			ID:        item.ID,
+			Preview:   item.Preview,
+			Kind:      item.Kind,
+			Pinned:    item.Pinned,
+			Sensitive: item.Sensitive,
+			Timestamp: item.Timestamp.Unix(),
+		})
+	}
+	return history, nil
+}
+
+// Copy снова помещает элемент в системный буфер обмена
+func (o *daemonObject) Copy(id string) *dbus.Error {
+	return busError(o.service.api.Call(ipc.MethodCopy, ipc.ItemRef{ID: id}, nil))
+}
+
+// Add добавляет содержимое в историю и возвращает ID элемента
+func (o *daemonObject) Add(content string) (string, *dbus.Error) {
+	var item ipc.Item
+	if err := o.service.api.Call(ipc.MethodAdd, ipc.AddParams{Content: content}, &item); err != nil {
+		return "", busError(err)
+	}
+	return item.ID, nil
+}
+
+// Delete удаляет элемент из истории
+func (o *daemonObject) Delete(id string) *dbus.Error {
+	return busError(o.service.api.Call(ipc.MethodDelete, ipc.ItemRef{ID: id}, nil))
+}
+
+// Pause приостанавливает захват буфера обмена на заданное число секунд,
+// 0 — до вызова Resume
+func (o *daemonObject) Pause(seconds uint32) *dbus.Error {
+	var params ipc.PauseParams
+	if seconds > 0 {
+		params.Duration = (time.Duration(seconds) * time.Second).String()
+	}
+	return busError(o.service.api.Call(ipc.MethodPause, params, nil))
+}
+
+// Resume возобновляет захват буфера обмена
+func (o *daemonObject) Resume() *dbus.Error {
+	return busError(o.service.api.Call(ipc.MethodResume, nil, nil))
+}
+
+// ShowPicker открывает выбор элемента истории
+func (o *daemonObject) ShowPicker() *dbus.Error {
+	if o.service.showPicker == nil {
+		return dbus.MakeFailedError(errors.New("no picker is available"))
+	}
+	return busError(o.service.showPicker())
+}
+
+// busError превращает ошибку API в ошибку D-Bus
+func busError(err error) *dbus.Error {
+	if err == nil {
+		return nil
+	}
+
+	var rpcErr *ipc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case ipc.CodeNotFound:
+			return dbus.NewError(ErrorNotFound, []interface{}{rpcErr.Message})
+		case ipc.CodeLocked:
+			return dbus.NewError(ErrorLocked, []interface{}{rpcErr.Message})
+		case ipc.CodeInvalidParams:
+			return dbus.NewError("org.freedesktop.DBus.Error.InvalidArgs", []interface{}{rpcErr.Message})
+		}
+	}
+	return dbus.MakeFailedError(err)
+}
@@ -0,0 +1,254 @@
+//go:build linux
+// +build linux
+
+package dbusservice
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+)
+
+// busConfig — конфигурация отдельного dbus-daemon для тестов
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startBus запускает отдельный dbus-daemon и возвращает его адрес.
+// Без dbus-daemon тест пропускается.
+func startBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(busConfig, filepath.Join(dir, "bus"))), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+configPath, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("dbus-daemon did not print its address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// startService публикует демон с пустой историей на отдельной шине и
+// возвращает менеджер истории и объект демона со стороны клиента
+func startService(t *testing.T, showPicker func() error) (*clipboard.Manager, dbus.BusObject, string) {
+	t.Helper()
+	address := startBus(t)
+
+	manager := clipboard.NewManager(nil, 10, nil)
+	api := ipc.NewServer(manager, nil)
+	manager.SetChangeCallback(api.Publish)
+
+	s, err := NewService(connect(t, address), api, showPicker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return manager, connect(t, address).Object(BusName, ObjectPath), address
+}
+
+func assertBusError(t *testing.T, err error, name string) {
+	t.Helper()
+	var busErr dbus.Error
+	if !errors.As(err, &busErr) || busErr.Name != name {
+		t.Fatalf("err = %v, want %s", err, name)
+	}
+}
+
+func TestMethods(t *testing.T) {
+	manager, daemon, _ := startService(t, nil)
+
+	var id string
+	if err := daemon.Call(Interface+".Add", 0, "hello").Store(&id); err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.Call(Interface+".Add", 0, "https://example.com").Store(new(string)); err != nil {
+		t.Fatal(err)
+	}
+
+	var history []HistoryItem
+	if err := daemon.Call(Interface+".GetHistory", 0, int32(0)).Store(&history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[1].ID != id || history[1].Preview != "hello" || history[0].Kind != "url" {
+		t.Fatalf("history = %+v", history)
+	}
+	if err := daemon.Call(Interface+".GetHistory", 0, int32(1)).Store(&history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("GetHistory(1) returned %d items", len(history))
+	}
+
+	if err := daemon.Call(Interface+".Delete", 0, id).Err; err != nil {
+		t.Fatal(err)
+	}
+	if n := len(manager.GetHistory()); n != 1 {
+		t.Fatalf("history has %d items after Delete, want 1", n)
+	}
+
+	if err := daemon.Call(Interface+".Pause", 0, uint32(60)).Err; err != nil {
+		t.Fatal(err)
+	}
+	if !manager.IsPaused() || manager.PausedUntil().IsZero() {
+		t.Fatal("Pause(60) did not pause capture for a while")
+	}
+	if err := daemon.Call(Interface+".Resume", 0).Err; err != nil {
+		t.Fatal(err)
+	}
+	if manager.IsPaused() {
+		t.Fatal("Resume did not resume capture")
+	}
+}
+
+func TestMethodErrors(t *testing.T) {
+	manager, daemon, _ := startService(t, nil)
+
+	assertBusError(t, daemon.Call(Interface+".Delete", 0, "000000000000").Err, ErrorNotFound)
+	assertBusError(t, daemon.Call(Interface+".Copy", 0, "000000000000").Err, ErrorNotFound)
+	assertBusError(t, daemon.Call(Interface+".Add", 0, "").Err, "org.freedesktop.DBus.Error.InvalidArgs")
+	assertBusError(t, daemon.Call(Interface+".GetHistory", 0, int32(-1)).Err, "org.freedesktop.DBus.Error.InvalidArgs")
+	assertBusError(t, daemon.Call(Interface+".ShowPicker", 0).Err, "org.freedesktop.DBus.Error.Failed")
+
+	manager.Lock()
+	assertBusError(t, daemon.Call(Interface+".GetHistory", 0, int32(0)).Err, ErrorLocked)
+	if err := daemon.Call(Interface+".Pause", 0, uint32(0)).Err; err != nil {
+		t.Fatalf("Pause while locked: %v", err)
+	}
+}
+
+func TestShowPicker(t *testing.T) {
+	called := make(chan struct{}, 1)
+	_, daemon, _ := startService(t, func() error {
+		called <- struct{}{}
+		return nil
+	})
+
+	if err := daemon.Call(Interface+".ShowPicker", 0).Err; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-called:
+	default:
+		t.Fatal("ShowPicker did not open the picker")
+	}
+}
+
+func TestSignals(t *testing.T) {
+	manager, daemon, address := startService(t, nil)
+
+	listener := connect(t, address)
+	if err := listener.AddMatchSignal(dbus.WithMatchInterface(Interface)); err != nil {
+		t.Fatal(err)
+	}
+	signals := make(chan *dbus.Signal, 10)
+	listener.Signal(signals)
+
+	next := func(name string) *dbus.Signal {
+		t.Helper()
+		for {
+			select {
+			case signal := <-signals:
+				if signal.Name == Interface+"."+name {
+					return signal
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("no %s signal", name)
+			}
+		}
+	}
+
+	var id string
+	if err := daemon.Call(Interface+".Add", 0, "hello").Store(&id); err != nil {
+		t.Fatal(err)
+	}
+	added := next(SignalItemAdded)
+	if len(added.Body) != 3 || added.Body[0] != id || added.Body[1] != "hello" || added.Body[2] != "text" {
+		t.Fatalf("ItemAdded body = %v", added.Body)
+	}
+
+	manager.IncrementClickCount(clipboard.ByID("hello"))
+	if used := next(SignalItemUsed); len(used.Body) != 1 || used.Body[0] != id {
+		t.Fatalf("ItemUsed body = %v", used.Body)
+	}
+}
+
+func TestNameTaken(t *testing.T) {
+	_, _, address := startService(t, nil)
+
+	api := ipc.NewServer(clipboard.NewManager(nil, 10, nil), nil)
+	if _, err := NewService(connect(t, address), api, nil); err == nil {
+		t.Fatal("NewService took a name owned by another daemon")
+	}
+}
+
+func TestCloseReleasesName(t *testing.T) {
+	address := startBus(t)
+	api := ipc.NewServer(clipboard.NewManager(nil, 10, nil), nil)
+
+	s, err := NewService(connect(t, address), api, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := NewService(connect(t, address), api, nil)
+	if err != nil {
+		t.Fatalf("name was not released by Close: %v", err)
+	}
+	again.Close()
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+// +build !linux
+
+package dbusservice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+)
+
+// Service недоступна на этой платформе
+type Service struct{}
+
+// Export сообщает, что служба D-Bus на этой платформе не поддерживается
+func Export(api *ipc.Server, showPicker func() error) (*Service, error) {
+	return nil, fmt.Errorf("D-Bus service is only available on Linux: %w", errors.ErrUnsupported)
+}
+
+// PeerChanged ничего не делает
+func (s *Service) PeerChanged(peer string, connected bool) {}
+
+// Close ничего не делает
+func (s *Service) Close() error {
+	return nil
+}
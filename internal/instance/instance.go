@@ -0,0 +1,97 @@
+// Package instance следит, чтобы у пользователя работал только один демон
+// smart-clipboard. Работающий демон держит исключительную блокировку файла
+// в каталоге времени выполнения, команды обращаются к нему через
+// управляющий сокет (см. пакет ipc).
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockFileName = "instance.lock"
+
+	acquireRetryInterval = 50 * time.Millisecond
+)
+
+// ErrRunning возвращается Acquire, если блокировку держит другой экземпляр
+var ErrRunning = errors.New("smart-clipboard is already running")
+
+// Instance — блокировка, которую держит работающий демон
+type Instance struct {
+	lockFile *os.File
+}
+
+// RuntimeDir возвращает каталог пользователя для файла блокировки и сокетов:
+// $XDG_RUNTIME_DIR/smart-clipboard, а без него — личный каталог пользователя
+// во временном каталоге системы.
+func RuntimeDir() (string, error) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir != "" {
+		dir = filepath.Join(dir, "smart-clipboard")
+	} else {
+		dir = filepath.Join(os.TempDir(), fmt.Sprintf("smart-clipboard-%d", os.Getuid()))
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	// В общем временном каталоге чужой каталог с тем же именем недопустим
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() || info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("runtime directory %s is not private", dir)
+	}
+	return dir, nil
+}
+
+// Acquire берёт блокировку единственного экземпляра. Если её уже держит
+// другой демон, возвращается ErrRunning.
+func Acquire() (*Instance, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, lockFileName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if errors.Is(err, errWouldBlock) {
+			return nil, ErrRunning
+		}
+		return nil, err
+	}
+
+	// PID — только для диагностики
+	file.Truncate(0)
+	fmt.Fprintf(file, "%d\n", os.Getpid())
+
+	return &Instance{lockFile: file}, nil
+}
+
+// AcquireWait работает как Acquire, но пока блокировку держит другой процесс,
+// повторяет попытки в течение timeout
+func AcquireWait(timeout time.Duration) (*Instance, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		inst, err := Acquire()
+		if !errors.Is(err, ErrRunning) || time.Now().After(deadline) {
+			return inst, err
+		}
+		time.Sleep(acquireRetryInterval)
+	}
+}
+
+// Release снимает блокировку
+func (i *Instance) Release() {
+	i.lockFile.Close()
+}
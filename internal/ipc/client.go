@@ -0,0 +1,132 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// dialTimeout ограничивает ожидание подключения к демону
+const dialTimeout = 2 * time.Second
+
+// Client — соединение с управляющим сокетом демона. Не безопасен
+// для одновременного использования из нескольких горутин.
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	encoder *json.Encoder
+	nextID  int
+}
+
+// Dial подключается к работающему демону
+func Dial() (*Client, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+
+	return &Client{
+		conn:    conn,
+		scanner: scanner,
+		encoder: json.NewEncoder(conn),
+	}, nil
+}
+
+// Close закрывает соединение
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Call вызывает метод и декодирует результат в result (может быть nil).
+// Ошибки демона возвращаются как *Error.
+func (c *Client) Call(method string, params, result any) error {
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+
+	req := Request{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+	if err := c.encoder.Encode(req); err != nil {
+		return err
+	}
+
+	for {
+		resp, err := c.read()
+		if err != nil {
+			return err
+		}
+		if string(resp.ID) != string(id) {
+			continue // Событие подписки или чужой ответ
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && resp.Result != nil {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	}
+}
+
+// Subscribe подписывается на изменения истории и вызывает handler для каждого
+// события, пока соединение не закроется или handler не вернёт ошибку
+func (c *Client) Subscribe(handler func(Event) error) error {
+	if err := c.Call(MethodSubscribe, nil, nil); err != nil {
+		return err
+	}
+
+	for {
+		resp, err := c.read()
+		if err != nil {
+			return err
+		}
+		if resp.Method != MethodEvent {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(resp.Params, &event); err != nil {
+			return err
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+}
+
+// clientResponse — ответ или уведомление в том виде, в котором его читает клиент
+type clientResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+func (c *Client) read() (clientResponse, error) {
+	var resp clientResponse
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return resp, err
+		}
+		return resp, fmt.Errorf("connection closed by the daemon")
+	}
+	err := json.Unmarshal(c.scanner.Bytes(), &resp)
+	return resp, err
+}
@@ -0,0 +1,358 @@
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+var (
+	errLocked   = &Error{Code: CodeLocked, Message: "history is locked"}
+	errNotFound = &Error{Code: CodeNotFound, Message: "item not found"}
+)
+
+// lockedMethods можно вызывать, пока история заблокирована: они не раскрывают её содержимое
+var lockedMethods = map[string]bool{
+	MethodStatus: true,
+	MethodPause:  true,
+	MethodResume: true,
+	MethodPeers:  true,
+	MethodReload: true,
+	MethodUnlock: true,
+}
+
+func errorResponse(req Request, err *Error) Response {
+	return Response{JSONRPC: "2.0", ID: req.ID, Error: err}
+}
+
+func (s *Server) handle(req Request) Response {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req, &Error{Code: CodeInvalidRequest, Message: "invalid request"})
+	}
+	if s.manager.IsLocked() && !lockedMethods[req.Method] {
+		return errorResponse(req, errLocked)
+	}
+
+	s.callMu.Lock()
+	result, err := s.call(req)
+	s.callMu.Unlock()
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		return errorResponse(req, rpcErr)
+	}
+	return Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) call(req Request) (any, error) {
+	switch req.Method {
+	case MethodList, MethodSearch:
+		var params ListParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.list(params), nil
+	case MethodGet:
+		var ref ItemRef
+		if err := decodeParams(req, &ref); err != nil {
+			return nil, err
+		}
+		return s.get(ref)
+	case MethodCopy:
+		var ref ItemRef
+		if err := decodeParams(req, &ref); err != nil {
+			return nil, err
+		}
+		return s.copy(ref)
+	case MethodAdd:
+		var params AddParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.add(params)
+	case MethodDelete:
+		var ref ItemRef
+		if err := decodeParams(req, &ref); err != nil {
+			return nil, err
+		}
+		if !s.manager.DeleteItem(selector(ref)) {
+			return nil, errNotFound
+		}
+		return true, nil
+	case MethodPin:
+		var params PinParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if !s.manager.SetPinned(selector(params.ItemRef), params.Pinned) {
+			return nil, errNotFound
+		}
+		return true, nil
+	case MethodTag:
+		var params TagParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if !s.manager.SetTags(selector(params.ItemRef), normalizeTags(params.Tags)) {
+			return nil, errNotFound
+		}
+		return true, nil
+	case MethodEdit:
+		var params EditParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		item, err := s.manager.EditItem(selector(params.ItemRef), params.Content)
+		if errors.Is(err, clipboard.ErrNotFound) {
+			return nil, errNotFound
+		}
+		if err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		return NewItem(item, Find(s.manager.GetHistory(), ItemRef{ID: ShortID(item)})), nil
+	case MethodClear:
+		s.manager.ClearHistory()
+		return true, nil
+	case MethodExport:
+		return s.export()
+	case MethodImport:
+		var params ImportParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.manager.Import(params.Items, params.Replace), nil
+	case MethodPause:
+		var params PauseParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		var duration time.Duration
+		if params.Duration != "" {
+			var err error
+			duration, err = time.ParseDuration(params.Duration)
+			if err != nil || duration < 0 {
+				return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("invalid duration %q", params.Duration)}
+			}
+		}
+		s.manager.Pause(duration)
+		return s.status(), nil
+	case MethodResume:
+		s.manager.Resume()
+		return s.status(), nil
+	case MethodStatus:
+		return s.status(), nil
+	case MethodPeers:
+		if s.syncManager == nil {
+			return []string{}, nil
+		}
+		return s.syncManager.Peers(), nil
+	case MethodReload:
+		if s.reload == nil {
+			return nil, &Error{Code: CodeMethodNotFound, Message: "reload is not supported"}
+		}
+		if err := s.reload(); err != nil {
+			return nil, &Error{Code: CodeInvalidConfig, Message: err.Error()}
+		}
+		return s.status(), nil
+	case MethodUnlock:
+		var params UnlockParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if s.unlock == nil {
+			return nil, &Error{Code: CodeMethodNotFound, Message: "history lock is not enabled"}
+		}
+		if err := s.unlock([]byte(params.Passphrase)); err != nil {
+			return nil, &Error{Code: CodeWrongKey, Message: err.Error()}
+		}
+		s.manager.Unlock()
+		return s.status(), nil
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// Call выполняет метод в текущем процессе, без сокета. Параметры и результат
+// проходят через JSON так же, как при вызове через Client.
+func (s *Server) Call(method string, params, result any) error {
+	req := Request{JSONRPC: "2.0", ID: json.RawMessage("0"), Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+
+	resp := s.handle(req)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || resp.Result == nil {
+		return nil
+	}
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func decodeParams(req Request, params any) error {
+	if len(req.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// normalizeTags убирает пробелы по краям, пустые теги и повторы
+func normalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// selector выбирает элемент по ссылке; поиск выполняется под блокировкой
+// истории менеджера вместе с действием над элементом
+func selector(ref ItemRef) clipboard.Selector {
+	return func(history []types.ClipboardItem) int {
+		return Find(history, ref)
+	}
+}
+
+func (s *Server) list(params ListParams) []Item {
+	query := strings.ToLower(params.Query)
+	items := []Item{}
+
+	for i, item := range s.manager.GetHistory() {
+		if params.Kind != "" && item.Kind != params.Kind {
+			continue
+		}
+		if query != "" && !s.manager.Matches(item, query) {
+			continue
+		}
+		if params.Tag != "" && !slices.Contains(item.Tags, params.Tag) {
+			continue
+		}
+		if params.Pinned != nil && item.Pinned != *params.Pinned {
+			continue
+		}
+		if !params.Since.IsZero() && item.Timestamp.Before(params.Since) {
+			continue
+		}
+		items = append(items, NewItem(item, i))
+		if params.Limit > 0 && len(items) == params.Limit {
+			break
+		}
+	}
+	return items
+}
+
+func (s *Server) get(ref ItemRef) (Item, error) {
+	item, i, ok := s.manager.Item(selector(ref))
+	if !ok {
+		return Item{}, errNotFound
+	}
+
+	content, err := s.manager.ItemContent(item)
+	if err != nil {
+		return Item{}, err
+	}
+
+	result := NewItem(item, i)
+	result.Content = content
+	return result, nil
+}
+
+func (s *Server) copy(ref ItemRef) (Item, error) {
+	item, _, ok := s.manager.Item(selector(ref))
+	if !ok {
+		return Item{}, errNotFound
+	}
+
+	content, err := s.manager.ItemContent(item)
+	if err != nil {
+		return Item{}, err
+	}
+	if err := s.manager.CopyToClipboard(content); err != nil {
+		return Item{}, err
+	}
+	s.manager.IncrementClickCount(clipboard.ByID(item.ID()))
+
+	history := s.manager.GetHistory()
+	return NewItem(item, Find(history, ItemRef{ID: ShortID(item)})), nil
+}
+
+func (s *Server) add(params AddParams) (Item, error) {
+	item, err := s.manager.Add(params.Content)
+	if err != nil {
+		return Item{}, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	if params.Pinned {
+		s.manager.SetPinned(clipboard.ByID(item.ID()), true)
+		item.Pinned = true
+	}
+
+	history := s.manager.GetHistory()
+	return NewItem(item, Find(history, ItemRef{ID: ShortID(item)})), nil
+}
+
+// export возвращает историю целиком; содержимое блобов встраивается
+// в элементы, чтобы экспорт не зависел от каталога блобов
+func (s *Server) export() ([]types.ClipboardItem, error) {
+	history := s.manager.GetHistory()
+	items := make([]types.ClipboardItem, 0, len(history))
+
+	for _, item := range history {
+		if item.BlobHash != "" {
+			content, err := s.manager.ItemContent(item)
+			if err != nil {
+				return nil, err
+			}
+			item.Content = content
+			item.BlobHash = ""
+			item.Size = 0
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *Server) status() Status {
+	status := Status{
+		PID:    os.Getpid(),
+		Paused: s.manager.IsPaused(),
+		Locked: s.manager.IsLocked(),
+	}
+	if until := s.manager.PausedUntil(); status.Paused && !until.IsZero() {
+		status.PausedUntil = &until
+	}
+	if !status.Locked {
+		status.Items = len(s.manager.GetHistory())
+	}
+	if s.syncManager != nil {
+		status.Sync = !s.syncManager.IsSuspended()
+		status.Peers = len(s.syncManager.Peers())
+	}
+	return status
+}
@@ -0,0 +1,269 @@
+package ipc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// newTestServer создаёт сервер над историей из contents; первый элемент — самый новый
+func newTestServer(t *testing.T, contents ...string) *Server {
+	t.Helper()
+	history := make([]types.ClipboardItem, 0, len(contents))
+	now := time.Now()
+	for i, content := range contents {
+		history = append(history, types.ClipboardItem{
+			Content:   content,
+			Preview:   content,
+			Kind:      clipboard.DetectKind(content),
+			Timestamp: now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	return NewServer(clipboard.NewManager(history, 10, nil), nil)
+}
+
+func assertCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != code {
+		t.Fatalf("err = %v, want code %d", err, code)
+	}
+}
+
+func previews(items []Item) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.Preview)
+	}
+	return result
+}
+
+func TestList(t *testing.T) {
+	s := newTestServer(t, "alpha", "beta", "https://example.com")
+
+	var items []Item
+	if err := s.Call(MethodList, nil, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].Index != 1 || items[0].Content != "" {
+		t.Fatalf("items = %+v, want 3 items without content", items)
+	}
+
+	if err := s.Call(MethodSearch, ListParams{Query: "ALP"}, &items); err != nil {
+		t.Fatal(err)
+	}
+	if got := previews(items); len(got) != 1 || got[0] != "alpha" {
+		t.Fatalf("search = %q, want [alpha]", got)
+	}
+
+	if err := s.Call(MethodList, ListParams{Kind: types.KindURL}, &items); err != nil {
+		t.Fatal(err)
+	}
+	if got := previews(items); len(got) != 1 || got[0] != "https://example.com" {
+		t.Fatalf("kind filter = %q, want the URL", got)
+	}
+
+	if err := s.Call(MethodList, ListParams{Limit: 2}, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("limit: got %d items, want 2", len(items))
+	}
+}
+
+func TestGet(t *testing.T) {
+	s := newTestServer(t, "alpha", "beta")
+
+	var item Item
+	if err := s.Call(MethodGet, ItemRef{Index: 2}, &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Content != "beta" || item.Index != 2 {
+		t.Fatalf("item = %+v, want beta at index 2", item)
+	}
+
+	var byID Item
+	if err := s.Call(MethodGet, ItemRef{ID: item.ID}, &byID); err != nil {
+		t.Fatal(err)
+	}
+	if byID.Content != "beta" {
+		t.Fatalf("get by ID returned %q", byID.Content)
+	}
+}
+
+func TestAddPinTagEditDelete(t *testing.T) {
+	s := newTestServer(t, "alpha")
+
+	var added Item
+	if err := s.Call(MethodAdd, AddParams{Content: "new", Pinned: true}, &added); err != nil {
+		t.Fatal(err)
+	}
+	if !added.Pinned || added.Index != 1 {
+		t.Fatalf("added = %+v, want a pinned first item", added)
+	}
+	ref := ItemRef{ID: added.ID}
+
+	if err := s.Call(MethodPin, PinParams{ItemRef: ref, Pinned: false}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Call(MethodTag, TagParams{ItemRef: ref, Tags: []string{" work ", "", "work", "misc"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var item Item
+	if err := s.Call(MethodGet, ref, &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Pinned || len(item.Tags) != 2 || item.Tags[0] != "work" || item.Tags[1] != "misc" {
+		t.Fatalf("item = %+v, want unpinned with tags [work misc]", item)
+	}
+
+	var edited Item
+	if err := s.Call(MethodEdit, EditParams{ItemRef: ref, Content: "changed"}, &edited); err != nil {
+		t.Fatal(err)
+	}
+	if edited.ID == added.ID || edited.Preview != "changed" || len(edited.Tags) != 2 {
+		t.Fatalf("edited = %+v, want a new ID with the tags kept", edited)
+	}
+	if err := s.Call(MethodGet, ref, nil); err == nil {
+		t.Fatal("the old ID still resolves after edit")
+	}
+
+	if err := s.Call(MethodDelete, ItemRef{ID: edited.ID}, nil); err != nil {
+		t.Fatal(err)
+	}
+	var items []Item
+	if err := s.Call(MethodList, nil, &items); err != nil {
+		t.Fatal(err)
+	}
+	if got := previews(items); len(got) != 1 || got[0] != "alpha" {
+		t.Fatalf("history after delete = %q, want [alpha]", got)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	s := newTestServer(t, "alpha")
+	missing := ItemRef{ID: "000000000000"}
+
+	calls := map[string]any{
+		MethodGet:    missing,
+		MethodCopy:   missing,
+		MethodDelete: ItemRef{Index: 5},
+		MethodPin:    PinParams{ItemRef: missing, Pinned: true},
+		MethodTag:    TagParams{ItemRef: missing, Tags: []string{"x"}},
+		MethodEdit:   EditParams{ItemRef: missing, Content: "x"},
+	}
+	for method, params := range calls {
+		t.Run(method, func(t *testing.T) {
+			assertCode(t, s.Call(method, params, nil), CodeNotFound)
+		})
+	}
+}
+
+func TestInvalidParams(t *testing.T) {
+	s := newTestServer(t, "alpha")
+
+	assertCode(t, s.Call(MethodAdd, AddParams{}, nil), CodeInvalidParams)
+	assertCode(t, s.Call(MethodEdit, EditParams{ItemRef: ItemRef{Index: 1}}, nil), CodeInvalidParams)
+	assertCode(t, s.Call(MethodPause, PauseParams{Duration: "soon"}, nil), CodeInvalidParams)
+	assertCode(t, s.Call(MethodGet, "not an object", nil), CodeInvalidParams)
+	assertCode(t, s.Call("frobnicate", nil, nil), CodeMethodNotFound)
+	assertCode(t, s.Call(MethodReload, nil, nil), CodeMethodNotFound)
+}
+
+func TestLocked(t *testing.T) {
+	s := newTestServer(t, "alpha")
+	s.manager.Lock()
+
+	for _, method := range []string{MethodList, MethodGet, MethodAdd, MethodDelete, MethodExport} {
+		assertCode(t, s.Call(method, nil, nil), CodeLocked)
+	}
+
+	var status Status
+	if err := s.Call(MethodStatus, nil, &status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Locked || status.Items != 0 {
+		t.Fatalf("status = %+v, want locked without the item count", status)
+	}
+
+	s.manager.Unlock()
+	if err := s.Call(MethodList, nil, nil); err != nil {
+		t.Fatalf("list after unlock: %v", err)
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	s := newTestServer(t, "one")
+	s.manager.Lock()
+	assertCode(t, s.Call(MethodUnlock, UnlockParams{Passphrase: "pass"}, nil), CodeMethodNotFound)
+
+	s.SetUnlockHandler(func(passphrase []byte) error {
+		if string(passphrase) != "pass" {
+			return errors.New("wrong passphrase")
+		}
+		return nil
+	})
+	assertCode(t, s.Call(MethodUnlock, UnlockParams{Passphrase: "nope"}, nil), CodeWrongKey)
+	if !s.manager.IsLocked() {
+		t.Fatal("wrong passphrase unlocked the history")
+	}
+
+	var status Status
+	if err := s.Call(MethodUnlock, UnlockParams{Passphrase: "pass"}, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Locked || status.Items != 1 {
+		t.Fatalf("status = %+v, want unlocked with one item", status)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	s := newTestServer(t)
+
+	var status Status
+	if err := s.Call(MethodPause, PauseParams{Duration: "1h"}, &status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Paused || status.PausedUntil == nil {
+		t.Fatalf("status = %+v, want paused with a deadline", status)
+	}
+	if err := s.Call(MethodResume, nil, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Paused {
+		t.Fatal("still paused after resume")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	s := newTestServer(t, "alpha", "beta")
+
+	var exported []types.ClipboardItem
+	if err := s.Call(MethodExport, nil, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 2 {
+		t.Fatalf("exported %d items, want 2", len(exported))
+	}
+
+	target := newTestServer(t, "gamma")
+	var imported int
+	if err := target.Call(MethodImport, ImportParams{Items: exported, Replace: true}, &imported); err != nil {
+		t.Fatal(err)
+	}
+	if imported != 2 {
+		t.Fatalf("imported = %d, want 2", imported)
+	}
+
+	var items []Item
+	if err := target.Call(MethodList, nil, &items); err != nil {
+		t.Fatal(err)
+	}
+	if got := previews(items); len(got) != 2 || got[0] != "alpha" || got[1] != "beta" {
+		t.Fatalf("history after import = %q, want [alpha beta]", got)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package ipc
+
+import "net"
+
+// checkPeer: на этой платформе учётные данные собеседника недоступны, доступ
+// ограничен правами личного каталога времени выполнения
+func checkPeer(c net.Conn) error {
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package ipc открывает доступ к работающему демону через Unix-сокет в
+// каталоге времени выполнения. Запросы и ответы — объекты JSON-RPC 2.0,
+// по одному в строке. Подключаться могут только процессы того же пользователя.
+package ipc
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/instance"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+const socketFileName = "control.sock"
+
+// Названия методов
+const (
+	MethodList      = "list"
+	MethodSearch    = "search"
+	MethodGet       = "get"
+	MethodCopy      = "copy"
+	MethodAdd       = "add"
+	MethodDelete    = "delete"
+	MethodPin       = "pin"
+	MethodTag       = "tag"
+	MethodEdit      = "edit"
+	MethodClear     = "clear"
+	MethodPause     = "pause"
+	MethodResume    = "resume"
+	MethodStatus    = "status"
+	MethodPeers     = "peers"
+	MethodExport    = "export"
+	MethodImport    = "import"
+	MethodSubscribe = "subscribe"
+	MethodReload    = "reload"
+	MethodUnlock    = "unlock"
+
+	// MethodEvent — уведомление, которое получают подписчики
+	MethodEvent = "event"
+)
+
+// Коды ошибок. Отрицательные коды ниже -32000 определены в JSON-RPC.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	CodeLocked        = -32000
+	CodeNotFound      = -32001
+	CodeInvalidConfig = -32002
+	CodeWrongKey      = -32003
+)
+
+// SocketPath возвращает путь к управляющему сокету
+func SocketPath() (string, error) {
+	dir, err := instance.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, socketFileName), nil
+}
+
+// Request — запрос JSON-RPC. У уведомлений нет ID.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response — ответ JSON-RPC, а с заполненным Method — уведомление
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error — объект ошибки JSON-RPC
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ItemRef выбирает элемент истории по короткому ID или по номеру в списке,
+// начиная с 1
+type ItemRef struct {
+	ID    string `json:"id,omitempty"`
+	Index int    `json:"index,omitempty"`
+}
+
+// ListParams — параметры list и search. Пустые поля не фильтруют.
+type ListParams struct {
+	Query  string    `json:"query,omitempty"`
+	Kind   string    `json:"kind,omitempty"`
+	Tag    string    `json:"tag,omitempty"`
+	Pinned *bool     `json:"pinned,omitempty"`
+	Since  time.Time `json:"since,omitempty"`
+	Limit  int       `json:"limit,omitempty"`
+}
+
+// AddParams — параметры add
+type AddParams struct {
+	Content string `json:"content"`
+	Pinned  bool   `json:"pinned,omitempty"`
+}
+
+// PinParams — параметры pin
+type PinParams struct {
+	ItemRef
+	Pinned bool `json:"pinned"`
+}
+
+// TagParams — параметры tag. Теги заменяют текущие.
+type TagParams struct {
+	ItemRef
+	Tags []string `json:"tags"`
+}
+
+// EditParams — параметры edit
+type EditParams struct {
+	ItemRef
+	Content string `json:"content"`
+}
+
+// ImportParams — параметры import
+type ImportParams struct {
+	Items   []types.ClipboardItem `json:"items"`
+	Replace bool                  `json:"replace,omitempty"`
+}
+
+// PauseParams — параметры pause. Без длительности пауза длится до resume.
+type PauseParams struct {
+	Duration string `json:"duration,omitempty"`
+}
+
+// UnlockParams — параметры unlock
+type UnlockParams struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// Item описывает элемент истории. Content заполняется, только когда
+// содержимое запрошено явно, поэтому списки не раскрывают конфиденциальное.
+type Item struct {
+	ID         string    `json:"id"`
+	Index      int       `json:"index"`
+	Preview    string    `json:"preview"`
+	Content    string    `json:"content,omitempty"`
+	Kind       string    `json:"kind,omitempty"`
+	Sensitive  bool      `json:"sensitive,omitempty"`
+	Pinned     bool      `json:"pinned,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	ClickCount int       `json:"click_count"`
+	Size       int       `json:"size"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Status описывает состояние демона
+type Status struct {
+	PID         int        `json:"pid"`
+	Paused      bool       `json:"paused"`
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	Locked      bool       `json:"locked"`
+	Items       int        `json:"items"`
+	Sync        bool       `json:"sync"`
+	Peers       int        `json:"peers"`
+}
+
+// Event отправляется подписчикам при каждом изменении истории
+type Event struct {
+	Op       string `json:"op"`
+	ID       string `json:"id,omitempty"`
+	Previous string `json:"previous,omitempty"` // edit: ID до изменения
+	Item     *Item  `json:"item,omitempty"`
+}
+
+// ShortID возвращает идентификатор элемента в API: первые 12 шестнадцатеричных
+// цифр SHA-256 от его полного ID
+func ShortID(item types.ClipboardItem) string {
+	return shortID(item.ID())
+}
+
+func shortID(id string) string {
+	return types.ContentHash([]byte(id))[:12]
+}
+
+// NewItem описывает элемент с индексом i в истории (с нуля). Превью
+// конфиденциального элемента скрывается.
+func NewItem(item types.ClipboardItem, i int) Item {
+	preview := item.Preview
+	if item.Sensitive {
+		preview = "••••••••"
+	}
+
+	return Item{
+		ID:         ShortID(item),
+		Index:      i + 1,
+		Preview:    preview,
+		Kind:       item.Kind,
+		Sensitive:  item.Sensitive,
+		Pinned:     item.Pinned,
+		Tags:       item.Tags,
+		ClickCount: item.ClickCount,
+		Size:       item.ContentSize(),
+		Timestamp:  item.Timestamp,
+	}
+}
+
+// Find возвращает индекс элемента, выбранного ref, или -1
+func Find(history []types.ClipboardItem, ref ItemRef) int {
+	if ref.ID == "" {
+		if ref.Index >= 1 && ref.Index <= len(history) {
+			return ref.Index - 1
+		}
+		return -1
+	}
+
+	for i, item := range history {
+		if ShortID(item) == ref.ID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,257 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	gosync "sync"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+	"github.com/yoshapihoff/smart-clipboard/internal/sync"
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+var logger = logging.For("ipc")
+
+// subscriberBuffer — сколько событий может ждать отправки подписчику,
+// прежде чем медленный подписчик будет отключён
+const subscriberBuffer = 64
+
+// maxRequestSize ограничивает размер одной строки запроса
+const maxRequestSize = 64 * 1024 * 1024
+
+// Server обслуживает управляющий сокет демона
+type Server struct {
+	manager     *clipboard.Manager
+	syncManager *sync.SyncManager
+	listener    net.Listener
+
+	mu          gosync.Mutex
+	subscribers map[*subscriber]bool
+
+	// Вызовы из сокета, веб-интерфейса и командной строки выполняются по очереди
+	callMu gosync.Mutex
+
+	reload func() error
+	unlock func(passphrase []byte) error
+}
+
+// subscriber — соединение, подписанное на события
+type subscriber struct {
+	events chan Event
+}
+
+// conn сериализует запись ответов и событий в одно соединение
+type conn struct {
+	net.Conn
+	writeMu gosync.Mutex
+	encoder *json.Encoder
+}
+
+// NewServer создаёт сервер для менеджера истории. syncManager может быть nil.
+func NewServer(manager *clipboard.Manager, syncManager *sync.SyncManager) *Server {
+	return &Server{
+		manager:     manager,
+		syncManager: syncManager,
+		subscribers: make(map[*subscriber]bool),
+	}
+}
+
+// SetReloadHandler задаёт функцию метода reload, перечитывающую конфигурацию.
+// Вызывается до Start.
+func (s *Server) SetReloadHandler(reload func() error) {
+	s.reload = reload
+}
+
+// SetUnlockHandler задаёт проверку пароля для метода unlock, например
+// Storage.CheckPassphrase. Без неё unlock недоступен. Вызывается до Start.
+func (s *Server) SetUnlockHandler(check func(passphrase []byte) error) {
+	s.unlock = check
+}
+
+// Start создаёт сокет и начинает принимать соединения. Вызывающий должен
+// удерживать блокировку единственного экземпляра, иначе можно удалить сокет
+// работающего демона.
+func (s *Server) Start() error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.StartListener(listener)
+	return nil
+}
+
+// StartListener начинает принимать соединения на готовом сокете, например
+// полученном от systemd при активации по сокету
+func (s *Server) StartListener(listener net.Listener) {
+	s.listener = listener
+	go s.acceptLoop()
+}
+
+// Stop закрывает сокет и отключает подписчиков
+func (s *Server) Stop() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		close(sub.events)
+		delete(s.subscribers, sub)
+	}
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Warn("accept failed", "err", err)
+			continue
+		}
+
+		if err := checkPeer(c); err != nil {
+			logger.Warn("rejected connection", "err", err)
+			c.Close()
+			continue
+		}
+
+		go s.serve(&conn{Conn: c, encoder: json.NewEncoder(c)})
+	}
+}
+
+func (s *Server) serve(c *conn) {
+	defer c.Close()
+
+	var sub *subscriber
+	defer func() {
+		if sub != nil {
+			s.unsubscribe(sub)
+		}
+	}()
+
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			c.write(Response{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: err.Error()}})
+			continue
+		}
+
+		if req.Method == MethodSubscribe && sub == nil {
+			if s.manager.IsLocked() {
+				c.write(errorResponse(req, errLocked))
+				continue
+			}
+			sub = s.subscribe()
+			go forwardEvents(c, sub)
+			c.write(Response{JSONRPC: "2.0", ID: req.ID, Result: true})
+			continue
+		}
+
+		resp := s.handle(req)
+		if req.ID != nil {
+			c.write(resp)
+		}
+	}
+}
+
+func (c *conn) write(resp Response) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.encoder.Encode(resp); err != nil {
+		c.Close()
+	}
+}
+
+func forwardEvents(c *conn, sub *subscriber) {
+	for event := range sub.events {
+		c.write(Response{JSONRPC: "2.0", Method: MethodEvent, Params: event})
+	}
+	// Канал закрыт: подписчик отстал или сервер остановлен
+	c.Close()
+}
+
+// Subscribe подписывается на события в текущем процессе. Канал закрывается
+// при отстающем чтении или остановке сервера; cancel отменяет подписку.
+func (s *Server) Subscribe() (events <-chan Event, cancel func()) {
+	sub := s.subscribe()
+	return sub.events, func() { s.unsubscribe(sub) }
+}
+
+func (s *Server) subscribe() *subscriber {
+	sub := &subscriber{events: make(chan Event, subscriberBuffer)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers[sub] = true
+	return sub
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers[sub] {
+		close(sub.events)
+		delete(s.subscribers, sub)
+	}
+}
+
+// Publish рассылает изменение истории подписчикам. Вызывается из callback
+// изменений менеджера.
+func (s *Server) Publish(change types.Change) {
+	event := s.newEvent(change)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			logger.Warn("dropping slow subscriber")
+			close(sub.events)
+			delete(s.subscribers, sub)
+		}
+	}
+}
+
+func (s *Server) newEvent(change types.Change) Event {
+	event := Event{Op: change.Op}
+
+	if change.Item != nil {
+		history := s.manager.GetHistory()
+		i := Find(history, ItemRef{ID: ShortID(*change.Item)})
+		item := NewItem(*change.Item, i)
+		if !change.Item.Sensitive {
+			item.Content = change.Item.Content
+		}
+		event.ID = item.ID
+		event.Item = &item
+		if change.Op == types.OpEdit {
+			event.Previous = shortID(change.Content)
+		}
+	} else if change.Content != "" {
+		event.ID = shortID(change.Content)
+	}
+
+	return event
+}
@@ -0,0 +1,98 @@
+package ipc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// startTestServer запускает сервер на сокете во временном каталоге времени выполнения
+func startTestServer(t *testing.T, contents ...string) *Server {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	s := newTestServer(t, contents...)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func dial(t *testing.T) *Client {
+	t.Helper()
+	client, err := Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClientCall(t *testing.T) {
+	startTestServer(t, "alpha", "beta")
+	client := dial(t)
+
+	var item Item
+	if err := client.Call(MethodGet, ItemRef{Index: 1}, &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Content != "alpha" {
+		t.Fatalf("content = %q, want alpha", item.Content)
+	}
+
+	// Ошибка демона возвращается клиенту как *Error, соединение остаётся рабочим
+	assertCode(t, client.Call(MethodGet, ItemRef{Index: 9}, nil), CodeNotFound)
+
+	var status Status
+	if err := client.Call(MethodStatus, nil, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Items != 2 {
+		t.Fatalf("status items = %d, want 2", status.Items)
+	}
+}
+
+func TestClientSubscribe(t *testing.T) {
+	s := startTestServer(t)
+	s.manager.SetChangeCallback(s.Publish)
+
+	events := make(chan Event, 1)
+	errStop := errors.New("stop")
+	subscriber := dial(t)
+	go func() {
+		subscriber.Subscribe(func(event Event) error {
+			events <- event
+			return errStop
+		})
+	}()
+
+	// Подписка оформляется асинхронно: добавляем, пока не придёт событие
+	client := dial(t)
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		if err := client.Call(MethodAdd, AddParams{Content: "item " + string(rune('a'+i%26))}, nil); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case event := <-events:
+			if event.Op != types.OpAdd || event.Item == nil || event.Item.Content == "" {
+				t.Fatalf("event = %+v, want add with content", event)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no event received")
+		}
+	}
+}
+
+func TestSubscribeWhileLocked(t *testing.T) {
+	s := startTestServer(t)
+	s.manager.Lock()
+
+	err := dial(t).Subscribe(func(Event) error { return nil })
+	assertCode(t, err, CodeLocked)
+}
@@ -0,0 +1,95 @@
+// Package lifecycle согласует корректное завершение демона. Фоновые циклы
+// работают с контекстом, который отменяется в начале завершения. Подсистемы
+// регистрируют обработчики остановки; они выполняются после выхода циклов
+// в обратном порядке, поэтому запущенное последним останавливается первым.
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+)
+
+var logger = logging.For("lifecycle")
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Lifecycle отслеживает фоновые циклы и обработчики остановки демона
+type Lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	hooks []hook
+
+	once sync.Once
+}
+
+// New создаёт Lifecycle с новым контекстом
+func New() *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Context возвращает контекст, который отменяется в начале завершения
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// Go запускает fn в горутине, завершения которой ждёт Shutdown. fn должна
+// вернуться после отмены контекста.
+func (l *Lifecycle) Go(fn func(ctx context.Context)) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		fn(l.ctx)
+	}()
+}
+
+// OnStop регистрирует обработчик остановки подсистемы. Переданный ему
+// контекст истекает вместе с тайм-аутом завершения.
+func (l *Lifecycle) OnStop(name string, stop func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook{name: name, stop: stop})
+}
+
+// Shutdown отменяет контекст, ждёт циклы, запущенные через Go, и выполняет
+// обработчики остановки в обратном порядке. Не успевшие выйти циклы
+// бросаются, а обработчики всё равно выполняются, чтобы история сохранилась.
+// Повторный вызов ничего не делает.
+func (l *Lifecycle) Shutdown(timeout time.Duration) {
+	l.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		l.cancel()
+
+		done := make(chan struct{})
+		go func() {
+			l.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			logger.Warn("background loops did not stop in time", "timeout", timeout)
+		}
+
+		l.mu.Lock()
+		hooks := l.hooks
+		l.mu.Unlock()
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i].stop(ctx); err != nil {
+				logger.Error("failed to stop", "name", hooks[i].name, "err", err)
+			}
+		}
+	})
+}
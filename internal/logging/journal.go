@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/systemd"
+)
+
+// journalHandler пишет записи в journald: сообщение с атрибутами в MESSAGE,
+// уровень в PRIORITY, каждый атрибут ещё и отдельным полем (SUBSYSTEM=sync)
+type journalHandler struct {
+	journal *systemd.JournalWriter
+	attrs   []slog.Attr
+	group   string
+}
+
+func (h *journalHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *journalHandler) Handle(_ context.Context, record slog.Record) error {
+	var message strings.Builder
+	message.WriteString(record.Message)
+	fields := make(map[string]string)
+
+	add := func(key string, value slog.Value) {
+		text := value.String()
+		if strings.ContainsAny(text, " \t\n\"=") {
+			text = strconv.Quote(text)
+		}
+		fmt.Fprintf(&message, " %s=%s", key, text)
+		if name := fieldName(key); name != "" {
+			fields[name] = value.String()
+		}
+	}
+	for _, attr := range h.attrs {
+		walkAttr("", attr, add)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		walkAttr(h.group, attr, add)
+		return true
+	})
+
+	return h.journal.Send(priority(record.Level), message.String(), fields)
+}
+
+func (h *journalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefixed := make([]slog.Attr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(prefixed, h.attrs)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + "." + attr.Key
+		}
+		prefixed = append(prefixed, attr)
+	}
+	return &journalHandler{journal: h.journal, attrs: prefixed, group: h.group}
+}
+
+func (h *journalHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	group := name
+	if h.group != "" {
+		group = h.group + "." + name
+	}
+	return &journalHandler{journal: h.journal, attrs: h.attrs, group: group}
+}
+
+// walkAttr раскрывает группы атрибутов в ключи через точку
+func walkAttr(prefix string, attr slog.Attr, add func(key string, value slog.Value)) {
+	value := attr.Value.Resolve()
+	key := attr.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	} else if key == "" {
+		key = prefix
+	}
+
+	if value.Kind() == slog.KindGroup {
+		for _, child := range value.Group() {
+			walkAttr(key, child, add)
+		}
+		return
+	}
+	if attr.Key == "" && value.Any() == nil {
+		return
+	}
+	add(key, value)
+}
+
+// fieldName переводит ключ атрибута в имя поля журнала: заглавные латинские
+// буквы, цифры и подчёркивания, не с подчёркивания или цифры
+func fieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	if name == "" || name[0] == '_' || name[0] >= '0' && name[0] <= '9' {
+		return ""
+	}
+	// Поля журнала с этими именами задаёт сам обработчик
+	switch name {
+	case "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER", "SYSLOG_PID":
+		return ""
+	}
+	return name
+}
+
+func priority(level slog.Level) systemd.Priority {
+	switch {
+	case level >= slog.LevelError:
+		return systemd.PriErr
+	case level >= slog.LevelWarn:
+		return systemd.PriWarning
+	case level >= slog.LevelInfo:
+		return systemd.PriInfo
+	default:
+		return systemd.PriDebug
+	}
+}
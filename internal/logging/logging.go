@@ -0,0 +1,258 @@
+// Package logging — общий для всех пакетов структурированный журнал с
+// уровнями. Каждая подсистема пишет через свой логгер (см. For), уровень
+// которого можно задать в конфигурации отдельно; режим отладки на ходу
+// поднимает все подсистемы до уровня debug. Вывод идёт в стандартный поток
+// ошибок, а под systemd — в журнал, и в ротируемый файл в каталоге
+// состояния. Сообщения стандартного пакета log проходят через те же
+// обработчики.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/systemd"
+)
+
+const (
+	// DefaultMaxSize — размер файла журнала, после которого он ротируется
+	DefaultMaxSize = 10 * 1024 * 1024
+	// DefaultMaxFiles — сколько старых файлов журнала хранится
+	DefaultMaxFiles = 3
+)
+
+// Config описывает вывод журнала и уровни
+type Config struct {
+	// Level — уровень по умолчанию: debug, info, warn или error
+	Level string
+	// Levels задаёт уровень отдельных подсистем, например "sync": "debug"
+	Levels map[string]string
+	// Debug поднимает все подсистемы до уровня debug
+	Debug bool
+
+	// File — путь к файлу журнала; пустой путь отключает файл
+	File     string
+	MaxSize  int64 // Размер в байтах, после которого файл ротируется
+	MaxFiles int   // Сколько старых файлов хранится
+}
+
+var (
+	// handler — текущий вывод, общий для логгеров всех подсистем
+	handler atomic.Pointer[slog.Handler]
+
+	levelsMu     sync.RWMutex
+	defaultLevel = slog.LevelInfo
+	levels       = map[string]slog.Level{}
+	debug        atomic.Bool
+
+	// closeOutput закрывает файл и подключение к журналу текущей конфигурации
+	closeOutput = func() error { return nil }
+)
+
+func init() {
+	setHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func setHandler(h slog.Handler) {
+	handler.Store(&h)
+}
+
+// For возвращает логгер подсистемы. Логгеры можно создавать до Setup:
+// они всегда используют текущие вывод и уровни.
+func For(subsystem string) *slog.Logger {
+	return slog.New(&subsystemHandler{subsystem: subsystem})
+}
+
+// Setup настраивает вывод и уровни и направляет стандартный пакет log через
+// подсистему "main". Повторный вызов меняет настройку.
+func Setup(cfg Config) error {
+	if err := SetLevels(cfg.Level, cfg.Levels); err != nil {
+		return err
+	}
+	SetDebug(cfg.Debug)
+
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handlers []slog.Handler
+	var closers []io.Closer
+
+	if systemd.JournalStream() {
+		if journal, err := systemd.NewJournalWriter("smart-clipboard"); err == nil {
+			handlers = append(handlers, &journalHandler{journal: journal})
+			closers = append(closers, journal)
+		}
+	}
+	if len(handlers) == 0 {
+		handlers = append(handlers, slog.NewTextHandler(os.Stderr, options))
+	}
+
+	if cfg.File != "" {
+		maxSize, maxFiles := cfg.MaxSize, cfg.MaxFiles
+		if maxSize <= 0 {
+			maxSize = DefaultMaxSize
+		}
+		if maxFiles <= 0 {
+			maxFiles = DefaultMaxFiles
+		}
+		file, err := openRotatingFile(cfg.File, maxSize, maxFiles)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		handlers = append(handlers, slog.NewTextHandler(file, options))
+		closers = append(closers, file)
+	}
+
+	previous := closeOutput
+	closeOutput = func() error {
+		for _, c := range closers {
+			c.Close()
+		}
+		return nil
+	}
+
+	if len(handlers) == 1 {
+		setHandler(handlers[0])
+	} else {
+		setHandler(multiHandler(handlers))
+	}
+	previous()
+
+	// Время и уровень добавляет обработчик, флаги log продублировали бы их
+	log.SetFlags(0)
+	slog.SetDefault(For("main"))
+	return nil
+}
+
+// Close сбрасывает и закрывает файл журнала. Следующие сообщения идут
+// в стандартный поток ошибок.
+func Close() error {
+	setHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	err := closeOutput()
+	closeOutput = func() error { return nil }
+	return err
+}
+
+// SetLevels задаёт уровень по умолчанию и уровни подсистем
+func SetLevels(level string, subsystems map[string]string) error {
+	parsedDefault := slog.LevelInfo
+	if level != "" {
+		if err := parsedDefault.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("invalid log level %q", level)
+		}
+	}
+
+	parsed := make(map[string]slog.Level, len(subsystems))
+	for name, value := range subsystems {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid log level %q for %s", value, name)
+		}
+		parsed[strings.ToLower(name)] = l
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	defaultLevel = parsedDefault
+	levels = parsed
+	return nil
+}
+
+// SetDebug включает или выключает режим отладки на ходу
+func SetDebug(enabled bool) {
+	debug.Store(enabled)
+}
+
+func enabled(subsystem string, level slog.Level) bool {
+	if debug.Load() {
+		return true
+	}
+
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	if l, ok := levels[subsystem]; ok {
+		return level >= l
+	}
+	return level >= defaultLevel
+}
+
+// subsystemHandler фильтрует записи по уровню подсистемы и передаёт их
+// текущему обработчику с атрибутом subsystem
+type subsystemHandler struct {
+	subsystem string
+	// Атрибуты и группы из With и WithGroup, применяются к текущему обработчику
+	apply []func(slog.Handler) slog.Handler
+}
+
+func (h *subsystemHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return enabled(h.subsystem, level)
+}
+
+func (h *subsystemHandler) Handle(ctx context.Context, record slog.Record) error {
+	out := (*handler.Load()).WithAttrs([]slog.Attr{slog.String("subsystem", h.subsystem)})
+	for _, apply := range h.apply {
+		out = apply(out)
+	}
+	return out.Handle(ctx, record)
+}
+
+func (h *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(out slog.Handler) slog.Handler { return out.WithAttrs(attrs) })
+}
+
+func (h *subsystemHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(out slog.Handler) slog.Handler { return out.WithGroup(name) })
+}
+
+func (h *subsystemHandler) with(apply func(slog.Handler) slog.Handler) slog.Handler {
+	return &subsystemHandler{
+		subsystem: h.subsystem,
+		apply:     append(append([]func(slog.Handler) slog.Handler{}, h.apply...), apply),
+	}
+}
+
+// multiHandler передаёт запись всем обработчикам
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, h := range m {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// DefaultFile возвращает путь к файлу журнала в каталоге состояния:
+// $XDG_STATE_HOME/smart-clipboard (~/.local/state) в Linux и BSD,
+// ~/Library/Logs/smart-clipboard в macOS и локальный каталог данных
+// приложений в Windows.
+func DefaultFile() (string, error) {
+	var dir string
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, "Library", "Logs")
+	case "windows":
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		dir = cache
+	default:
+		dir = os.Getenv("XDG_STATE_HOME")
+		if !filepath.IsAbs(dir) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			dir = filepath.Join(home, ".local", "state")
+		}
+	}
+	return filepath.Join(dir, "smart-clipboard", "smart-clipboard.log"), nil
+}
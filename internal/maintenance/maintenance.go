@@ -0,0 +1,72 @@
+// Package maintenance выполняет периодические фоновые задачи демона:
+// применение политики хранения, сжатие журнала, резервные копии и удаление
+// неиспользуемых блобов.
+package maintenance
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+)
+
+var logger = logging.For("maintenance")
+
+// Task — задача, которая выполняется каждые Interval
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler выполняет каждую задачу по своему таймеру до остановки
+type Scheduler struct {
+	tasks    []Task
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewScheduler создаёт планировщик для задач. Задачи с неположительным
+// интервалом отключены.
+func NewScheduler(tasks ...Task) *Scheduler {
+	return &Scheduler{
+		tasks:    tasks,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start запускает все задачи в фоне
+func (s *Scheduler) Start() {
+	for _, task := range s.tasks {
+		if task.Interval <= 0 {
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.loop(task)
+	}
+}
+
+// Stop останавливает задачи и ждёт завершения выполняющихся
+func (s *Scheduler) Stop() {
+	close(s.stopChan)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(task Task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := task.Run(); err != nil {
+				logger.Error("task failed", "task", task.Name, "err", err)
+			}
+		case <-s.stopChan:
+			return
+		}
+	}
+}
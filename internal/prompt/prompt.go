@@ -0,0 +1,82 @@
+// Package prompt запрашивает у пользователя секреты, например пароль истории.
+// Если подключён терминал, ввод читается из него, иначе показывается диалог
+// рабочего стола (zenity/kdialog в Linux, osascript в macOS, PowerShell
+// в Windows): в самом трее поля ввода нет.
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrCancelled возвращается, если пользователь закрыл диалог
+var ErrCancelled = errors.New("prompt cancelled")
+
+// Passphrase запрашивает секрет, не показывая вводимые символы
+func Passphrase(title string) ([]byte, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "%s: ", title)
+		secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		return secret, nil
+	}
+
+	return dialog(title)
+}
+
+// NewPassphrase запрашивает секрет дважды и проверяет, что введено одно и то же
+func NewPassphrase(title string) ([]byte, error) {
+	first, err := Passphrase(title)
+	if err != nil {
+		return nil, err
+	}
+	second, err := Passphrase(title + " (again)")
+	if err != nil {
+		return nil, err
+	}
+	if string(first) != string(second) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return first, nil
+}
+
+func dialog(title string) ([]byte, error) {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf(`text returned of (display dialog %q default answer "" with hidden answer with title "Smart clipboard")`, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`$s = Read-Host -AsSecureString -Prompt '%s'; [Runtime.InteropServices.Marshal]::PtrToStringAuto([Runtime.InteropServices.Marshal]::SecureStringToBSTR($s))`, strings.ReplaceAll(title, "'", "''"))
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		if path, err := exec.LookPath("zenity"); err == nil {
+			cmd = exec.Command(path, "--password", "--title", title)
+		} else if path, err := exec.LookPath("kdialog"); err == nil {
+			cmd = exec.Command(path, "--password", title)
+		} else {
+			return nil, errors.New("no terminal attached and neither zenity nor kdialog is installed")
+		}
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+
+	return []byte(strings.TrimRight(string(output), "\r\n")), nil
+}
@@ -0,0 +1,129 @@
+// Package session реагирует на события сеанса рабочего стола: блокировку
+// экрана и переход в сон. В Linux события приходят от logind и хранителя
+// экрана через D-Bus, на остальных платформах они пока не поддерживаются.
+package session
+
+import (
+	"fmt"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+	"github.com/yoshapihoff/smart-clipboard/internal/sync"
+)
+
+var logger = logging.For("session")
+
+// Event — изменение состояния сеанса
+type Event int
+
+const (
+	// Locked — экран заблокирован или включился хранитель экрана
+	Locked Event = iota
+	// Unlocked — экран разблокирован или компьютер вышел из сна
+	Unlocked
+	// Sleeping — компьютер вот-вот перейдёт в сон
+	Sleeping
+)
+
+func (e Event) String() string {
+	switch e {
+	case Locked:
+		return "locked"
+	case Unlocked:
+		return "unlocked"
+	case Sleeping:
+		return "sleeping"
+	default:
+		return fmt.Sprintf("event(%d)", int(e))
+	}
+}
+
+// Действия, которые можно задать в screen_lock_actions
+const (
+	ActionPause          = "pause"
+	ActionLock           = "lock"
+	ActionClearClipboard = "clear_clipboard"
+	ActionStopSync       = "stop_sync"
+)
+
+// Reactor выполняет заданные действия при блокировке сеанса и отменяет
+// обратимые (pause, stop_sync) при разблокировке. История, заблокированная
+// действием lock, остаётся заблокированной до ввода пароля.
+type Reactor struct {
+	manager     *clipboard.Manager
+	syncManager *sync.SyncManager
+	actions     map[string]bool
+
+	locked       bool
+	pausedByLock bool
+}
+
+// NewReactor проверяет названия действий из конфигурации
+func NewReactor(manager *clipboard.Manager, syncManager *sync.SyncManager, actions []string) (*Reactor, error) {
+	r := &Reactor{
+		manager:     manager,
+		syncManager: syncManager,
+		actions:     make(map[string]bool),
+	}
+
+	for _, action := range actions {
+		switch action {
+		case ActionPause, ActionLock, ActionClearClipboard, ActionStopSync:
+			r.actions[action] = true
+		default:
+			return nil, fmt.Errorf("unknown screen lock action %q", action)
+		}
+	}
+
+	return r, nil
+}
+
+// Run обрабатывает события, пока канал не закрыт
+func (r *Reactor) Run(events <-chan Event) {
+	for event := range events {
+		r.Handle(event)
+	}
+}
+
+// Handle выполняет действия для одного события. Повторная блокировка
+// (например, и от logind, и от хранителя экрана) применяется один раз.
+func (r *Reactor) Handle(event Event) {
+	switch event {
+	case Locked, Sleeping:
+		if r.locked {
+			return
+		}
+		r.locked = true
+		logger.Info("applying screen lock actions", "event", event)
+
+		if r.actions[ActionPause] && !r.manager.IsPaused() {
+			r.manager.Pause(0)
+			r.pausedByLock = true
+		}
+		if r.actions[ActionLock] {
+			r.manager.Lock()
+		}
+		if r.actions[ActionClearClipboard] {
+			if err := r.manager.ClearClipboard(); err != nil {
+				logger.Warn("failed to clear clipboard", "err", err)
+			}
+		}
+		if r.actions[ActionStopSync] && r.syncManager != nil {
+			r.syncManager.SetSuspended(true)
+		}
+	case Unlocked:
+		if !r.locked {
+			return
+		}
+		r.locked = false
+		logger.Info("reverting screen lock actions", "event", event)
+
+		if r.pausedByLock {
+			r.manager.Resume()
+			r.pausedByLock = false
+		}
+		if r.actions[ActionStopSync] && r.syncManager != nil {
+			r.syncManager.SetSuspended(false)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+//go:build linux
+// +build linux
+
+package session
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	login1Service          = "org.freedesktop.login1"
+	login1Path             = "/org/freedesktop/login1"
+	login1ManagerInterface = "org.freedesktop.login1.Manager"
+	login1SessionInterface = "org.freedesktop.login1.Session"
+)
+
+// Интерфейсы хранителей экрана с сигналом ActiveChanged(bool) на сессионной шине
+var screensaverInterfaces = []string{
+	"org.freedesktop.ScreenSaver",
+	"org.gnome.ScreenSaver",
+	"org.mate.ScreenSaver",
+	"org.cinnamon.ScreenSaver",
+}
+
+// Watcher превращает сигналы D-Bus от logind и хранителя экрана в события сеанса
+type Watcher struct {
+	systemConn  *dbus.Conn
+	sessionConn *dbus.Conn
+	signals     chan *dbus.Signal
+	events      chan Event
+}
+
+// Watch подключается к системной и сессионной шинам текущего пользователя.
+// Одна из шин может быть недоступна, ошибка возвращается, только если нет обеих.
+func Watch() (*Watcher, error) {
+	systemConn, systemErr := dbus.ConnectSystemBus()
+	if systemErr != nil {
+		systemConn = nil
+	}
+	sessionConn, sessionErr := dbus.ConnectSessionBus()
+	if sessionErr != nil {
+		sessionConn = nil
+	}
+
+	if systemConn == nil && sessionConn == nil {
+		return nil, fmt.Errorf("no D-Bus connection: system bus: %v, session bus: %v", systemErr, sessionErr)
+	}
+
+	return NewWatcher(systemConn, sessionConn)
+}
+
+// NewWatcher подписывается на сигналы сеанса в переданных подключениях:
+// по системному приходят сигналы logind, по сессионному — хранителя экрана.
+// Любое из них может быть nil. Подключения к отдельному dbus-daemon
+// позволяют проверять Watcher без рабочего стола.
+func NewWatcher(systemConn, sessionConn *dbus.Conn) (*Watcher, error) {
+	w := &Watcher{
+		systemConn:  systemConn,
+		sessionConn: sessionConn,
+		signals:     make(chan *dbus.Signal, 16),
+		events:      make(chan Event, 16),
+	}
+
+	if systemConn != nil {
+		if err := w.subscribeLogind(); err != nil {
+			return nil, err
+		}
+		systemConn.Signal(w.signals)
+	}
+
+	if sessionConn != nil {
+		for _, iface := range screensaverInterfaces {
+			err := sessionConn.AddMatchSignal(
+				dbus.WithMatchInterface(iface),
+				dbus.WithMatchMember("ActiveChanged"),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to subscribe to %s: %w", iface, err)
+			}
+		}
+		sessionConn.Signal(w.signals)
+	}
+
+	go w.loop()
+	return w, nil
+}
+
+// subscribeLogind подписывается на Lock/Unlock своего сеанса и PrepareForSleep.
+// Если объект сеанса найти не удалось (нет logind, например на отдельной
+// шине), принимаются Lock/Unlock любого сеанса.
+func (w *Watcher) subscribeLogind() error {
+	lockOptions := []dbus.MatchOption{dbus.WithMatchInterface(login1SessionInterface)}
+	if path, err := w.sessionPath(); err == nil {
+		lockOptions = append(lockOptions, dbus.WithMatchObjectPath(path))
+	} else {
+		logger.Warn("failed to resolve logind session, watching all sessions", "err", err)
+	}
+
+	for _, member := range []string{"Lock", "Unlock"} {
+		options := append(append([]dbus.MatchOption{}, lockOptions...), dbus.WithMatchMember(member))
+		if err := w.systemConn.AddMatchSignal(options...); err != nil {
+			return fmt.Errorf("failed to subscribe to logind %s: %w", member, err)
+		}
+	}
+
+	err := w.systemConn.AddMatchSignal(
+		dbus.WithMatchInterface(login1ManagerInterface),
+		dbus.WithMatchMember("PrepareForSleep"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to logind PrepareForSleep: %w", err)
+	}
+	return nil
+}
+
+func (w *Watcher) sessionPath() (dbus.ObjectPath, error) {
+	manager := w.systemConn.Object(login1Service, login1Path)
+
+	var path dbus.ObjectPath
+	var err error
+	if id := os.Getenv("XDG_SESSION_ID"); id != "" {
+		err = manager.Call(login1ManagerInterface+".GetSession", 0, id).Store(&path)
+	} else {
+		err = manager.Call(login1ManagerInterface+".GetSessionByPID", 0, uint32(os.Getpid())).Store(&path)
+	}
+	return path, err
+}
+
+func (w *Watcher) loop() {
+	defer close(w.events)
+
+	for signal := range w.signals {
+		if event, ok := translate(signal); ok {
+			w.events <- event
+		}
+	}
+}
+
+func translate(signal *dbus.Signal) (Event, bool) {
+	switch signal.Name {
+	case login1SessionInterface + ".Lock":
+		return Locked, true
+	case login1SessionInterface + ".Unlock":
+		return Unlocked, true
+	case login1ManagerInterface + ".PrepareForSleep":
+		sleeping, ok := firstBool(signal)
+		if !ok {
+			return 0, false
+		}
+		if sleeping {
+			return Sleeping, true
+		}
+		// Пробуждение снимает то, что было сделано при засыпании
+		return Unlocked, true
+	}
+
+	for _, iface := range screensaverInterfaces {
+		if signal.Name == iface+".ActiveChanged" {
+			active, ok := firstBool(signal)
+			if !ok {
+				return 0, false
+			}
+			if active {
+				return Locked, true
+			}
+			return Unlocked, true
+		}
+	}
+
+	return 0, false
+}
+
+func firstBool(signal *dbus.Signal) (bool, bool) {
+	if len(signal.Body) == 0 {
+		return false, false
+	}
+	value, ok := signal.Body[0].(bool)
+	return value, ok
+}
+
+// Events возвращает канал событий сеанса; его закрывает Close
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Close прекращает наблюдение и закрывает оба подключения
+func (w *Watcher) Close() error {
+	if w.systemConn != nil {
+		w.systemConn.RemoveSignal(w.signals)
+		w.systemConn.Close()
+	}
+	if w.sessionConn != nil {
+		w.sessionConn.RemoveSignal(w.signals)
+		w.sessionConn.Close()
+	}
+	close(w.signals)
+	return nil
+}
@@ -0,0 +1,160 @@
+//go:build linux
+// +build linux
+
+package session
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// busConfig — конфигурация отдельного dbus-daemon для тестов
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startBus запускает отдельный dbus-daemon и возвращает его адрес.
+// Без dbus-daemon тест пропускается.
+func startBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(busConfig, filepath.Join(dir, "bus"))), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+configPath, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("dbus-daemon did not print its address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func expectEvent(t *testing.T, w *Watcher, want Event) {
+	t.Helper()
+	select {
+	case event := <-w.Events():
+		if event != want {
+			t.Fatalf("event = %v, want %v", event, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %v event", want)
+	}
+}
+
+func TestWatcherSignals(t *testing.T) {
+	systemBus := startBus(t)
+	sessionBus := startBus(t)
+
+	systemConn, err := dbus.Connect(systemBus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionConn, err := dbus.Connect(sessionBus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWatcher(systemConn, sessionConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logind := connect(t, systemBus)
+	screensaver := connect(t, sessionBus)
+	emit := func(conn *dbus.Conn, path dbus.ObjectPath, name string, args ...interface{}) {
+		t.Helper()
+		if err := conn.Emit(path, name, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	emit(logind, "/org/freedesktop/login1/session/_31", login1SessionInterface+".Lock")
+	expectEvent(t, w, Locked)
+	emit(logind, "/org/freedesktop/login1/session/_31", login1SessionInterface+".Unlock")
+	expectEvent(t, w, Unlocked)
+	emit(logind, login1Path, login1ManagerInterface+".PrepareForSleep", true)
+	expectEvent(t, w, Sleeping)
+	emit(logind, login1Path, login1ManagerInterface+".PrepareForSleep", false)
+	expectEvent(t, w, Unlocked)
+
+	emit(screensaver, "/org/gnome/ScreenSaver", "org.gnome.ScreenSaver.ActiveChanged", true)
+	expectEvent(t, w, Locked)
+	emit(screensaver, "/ScreenSaver", "org.freedesktop.ScreenSaver.ActiveChanged", false)
+	expectEvent(t, w, Unlocked)
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Fatal("unexpected event after Close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Events was not closed")
+	}
+}
+
+func TestWatcherSessionBusOnly(t *testing.T) {
+	address := startBus(t)
+	sessionConn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWatcher(nil, sessionConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := connect(t, address).Emit("/org/mate/ScreenSaver", "org.mate.ScreenSaver.ActiveChanged", true); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, w, Locked)
+}
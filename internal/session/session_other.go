@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package session
+
+import "errors"
+
+// Watcher недоступен на этой платформе
+type Watcher struct{}
+
+// Watch сообщает, что события сеанса на этой платформе не поддерживаются
+func Watch() (*Watcher, error) {
+	return nil, errors.New("session events are only supported on Linux")
+}
+
+// Events возвращает nil-канал
+func (w *Watcher) Events() <-chan Event {
+	return nil
+}
+
+// Close ничего не делает
+func (w *Watcher) Close() error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+)
+
+func TestNewReactorUnknownAction(t *testing.T) {
+	if _, err := NewReactor(clipboard.NewManager(nil, 10, nil), nil, []string{"explode"}); err == nil {
+		t.Fatal("NewReactor accepted an unknown action")
+	}
+}
+
+func TestReactorPauseAndLock(t *testing.T) {
+	manager := clipboard.NewManager(nil, 10, nil)
+	r, err := NewReactor(manager, nil, []string{ActionPause, ActionLock, ActionStopSync})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Handle(Locked)
+	if !manager.IsPaused() || !manager.IsLocked() {
+		t.Fatal("screen lock did not pause and lock the history")
+	}
+
+	// Повторная блокировка от другого источника ничего не меняет
+	r.Handle(Sleeping)
+
+	r.Handle(Unlocked)
+	if manager.IsPaused() {
+		t.Fatal("capture is still paused after unlock")
+	}
+	if !manager.IsLocked() {
+		t.Fatal("history was unlocked without a passphrase")
+	}
+}
+
+func TestReactorKeepsUserPause(t *testing.T) {
+	manager := clipboard.NewManager(nil, 10, nil)
+	r, err := NewReactor(manager, nil, []string{ActionPause})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Pause(0)
+	r.Handle(Locked)
+	r.Handle(Unlocked)
+	if !manager.IsPaused() {
+		t.Fatal("unlock resumed a pause set by the user")
+	}
+}
+
+func TestReactorUnlockWithoutLock(t *testing.T) {
+	manager := clipboard.NewManager(nil, 10, nil)
+	r, err := NewReactor(manager, nil, []string{ActionPause})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Pause(0)
+	r.Handle(Unlocked)
+	if !manager.IsPaused() {
+		t.Fatal("unlock without a preceding lock resumed capture")
+	}
+}
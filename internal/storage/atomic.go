@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic записывает данные во временный файл рядом с целевым,
+// сбрасывает его на диск и переименовывает поверх целевого. При сбое
+// посреди записи на диске остаётся либо старая, либо новая версия файла.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, tempPattern(path))
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Временный файл удаляется при любой ошибке до переименования
+	ok := false
+	defer func() {
+		if !ok {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	ok = true
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir сбрасывает на диск запись каталога, чтобы переименование пережило
+// сбой питания. На платформах, где каталог нельзя открыть, ошибка игнорируется.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// tempPattern возвращает шаблон имени временного файла для path
+func tempPattern(path string) string {
+	return "." + filepath.Base(path) + ".tmp-*"
+}
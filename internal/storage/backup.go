@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultBackupCount — число хранимых поколений резервных копий
+const defaultBackupCount = 3
+
+// backupInterval — минимальный интервал между поколениями резервных копий.
+// История сохраняется часто, и без него все копии были бы почти одинаковыми.
+const backupInterval = 10 * time.Minute
+
+// SetBackupCount задаёт число поколений резервных копий (0 — не создавать)
+func (s *Storage) SetBackupCount(count int) {
+	s.backupCount.Store(int32(count))
+}
+
+// backupPath возвращает путь к резервной копии поколения n (1 — самая новая)
+func (s *Storage) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", s.filePath, n)
+}
+
+// rotateBackups сдвигает поколения резервных копий и копирует текущий файл
+// истории в первое поколение. Повреждённый файл не копируется, чтобы не
+// вытеснить исправные копии.
+func (s *Storage) rotateBackups(force bool) error {
+	count := int(s.backupCount.Load())
+	if count <= 0 {
+		return nil
+	}
+
+	if info, err := os.Stat(s.backupPath(1)); err == nil {
+		if !force && time.Since(info.ModTime()) < backupInterval {
+			return nil
+		}
+		// Файл не менялся с последней резервной копии
+		if primary, err := os.Stat(s.filePath); err == nil && !primary.ModTime().After(info.ModTime()) {
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := s.decode(data); err != nil {
+		return fmt.Errorf("not backing up damaged %s: %w", s.filePath, err)
+	}
+
+	os.Remove(s.backupPath(count))
+	for n := count - 1; n >= 1; n-- {
+		if err := os.Rename(s.backupPath(n), s.backupPath(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return WriteFileAtomic(s.backupPath(1), data, s.fileMode())
+}
+
+// Backup создаёт новое поколение резервной копии без учёта интервала,
+// если файл истории изменился с момента последней копии
+func (s *Storage) Backup() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rotateBackups(true)
+}
+
+// removeBackups удаляет все резервные копии. Используется после смены ключа,
+// чтобы на диске не оставалось данных, доступных по старому ключу или без него.
+func (s *Storage) removeBackups() {
+	matches, _ := filepath.Glob(s.filePath + ".[0-9]*")
+	for _, path := range matches {
+		os.Remove(path)
+	}
+}
+
+// backupPaths возвращает существующие резервные копии, от новых к старым
+func (s *Storage) backupPaths() []string {
+	count := int(s.backupCount.Load())
+	var paths []string
+	for n := 1; ; n++ {
+		path := s.backupPath(n)
+		if _, err := os.Stat(path); err != nil {
+			if n > count {
+				break
+			}
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// recoverFromBackup загружает историю из самой новой исправной резервной копии
+func (s *Storage) recoverFromBackup(primaryErr error) (*loadedFile, error) {
+	for _, path := range s.backupPaths() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		history, err := s.decode(data)
+		if err != nil {
+			continue
+		}
+
+		logger.Warn("history file is damaged, recovered from backup", "file", s.filePath, "err", primaryErr, "items", len(history), "backup", path)
+		return &loadedFile{data: data, history: history}, nil
+	}
+
+	return nil, primaryErr
+}
+
+// FileStatus описывает состояние одного файла истории
+type FileStatus struct {
+	Path    string
+	Missing bool
+	Items   int
+	Err     error
+}
+
+func (f FileStatus) String() string {
+	switch {
+	case f.Missing:
+		return fmt.Sprintf("%s: missing", f.Path)
+	case f.Err != nil:
+		return fmt.Sprintf("%s: DAMAGED: %v", f.Path, f.Err)
+	default:
+		return fmt.Sprintf("%s: ok, %d items", f.Path, f.Items)
+	}
+}
+
+// VerifyReport — результат проверки файла истории и резервных копий
+type VerifyReport struct {
+	Primary   FileStatus
+	Backups   []FileStatus
+	TempFiles []string // Остатки прерванных записей
+}
+
+// Healthy сообщает, что основной файл исправен и мусора не осталось
+func (r VerifyReport) Healthy() bool {
+	return r.Primary.Err == nil && len(r.TempFiles) == 0
+}
+
+// Verify проверяет основной файл истории, резервные копии и временные файлы
+func (s *Storage) Verify() VerifyReport {
+	report := VerifyReport{Primary: s.checkFile(s.filePath)}
+	for _, path := range s.backupPaths() {
+		report.Backups = append(report.Backups, s.checkFile(path))
+	}
+	report.TempFiles, _ = filepath.Glob(filepath.Join(filepath.Dir(s.filePath), tempPattern(s.filePath)))
+	return report
+}
+
+func (s *Storage) checkFile(path string) FileStatus {
+	status := FileStatus{Path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		status.Missing = true
+		return status
+	}
+	if err != nil {
+		status.Err = err
+		return status
+	}
+
+	history, err := s.decode(data)
+	status.Items = len(history)
+	status.Err = err
+	return status
+}
+
+// Repair удаляет остатки прерванных записей и восстанавливает повреждённый
+// основной файл из самой новой исправной резервной копии
+func (s *Storage) Repair() error {
+	report := s.Verify()
+
+	for _, path := range report.TempFiles {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	if report.Primary.Err == nil {
+		return nil
+	}
+	if errors.Is(report.Primary.Err, ErrLocked) {
+		return ErrLocked
+	}
+
+	recovered, err := s.recoverFromBackup(report.Primary.Err)
+	if err != nil {
+		return fmt.Errorf("no valid backup to restore from: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return WriteFileAtomic(s.filePath, recovered.data, s.fileMode())
+}
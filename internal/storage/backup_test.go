@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new" {
+		t.Fatalf("content = %q, want %q", data, "new")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Временных файлов не остаётся
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("directory contains %d entries, want 1", len(entries))
+	}
+}
+
+func TestWriteFileAtomicFailureKeepsOldFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+
+	// Переименование поверх непустого каталога невозможно
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "keep"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("new"), 0644); err == nil {
+		t.Fatal("WriteFileAtomic replaced a directory")
+	}
+
+	temps, _ := filepath.Glob(filepath.Join(dir, tempPattern(path)))
+	if len(temps) != 0 {
+		t.Fatalf("temporary files left behind: %v", temps)
+	}
+}
+
+// ageBackups сдвигает время изменения резервных копий в прошлое, чтобы
+// следующее сохранение создало новое поколение
+func ageBackups(t *testing.T, s *Storage) {
+	t.Helper()
+	past := time.Now().Add(-2 * backupInterval)
+	for _, path := range s.backupPaths() {
+		if err := os.Chtimes(path, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBackupRotation(t *testing.T) {
+	s := newTestStorage(t)
+	s.SetBackupCount(2)
+
+	for _, content := range []string{"one", "two", "three", "four"} {
+		ageBackups(t, s)
+		if err := s.SaveHistory(testItems(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths := s.backupPaths()
+	if len(paths) != 2 {
+		t.Fatalf("backups = %v, want 2 generations", paths)
+	}
+	for i, want := range []string{"three", "two"} {
+		data, err := os.ReadFile(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		history, err := s.decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertContents(t, history, want)
+	}
+}
+
+func TestBackupIntervalSkipsRecentBackup(t *testing.T) {
+	s := newTestStorage(t)
+
+	for _, content := range []string{"one", "two", "three"} {
+		if err := s.SaveHistory(testItems(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if paths := s.backupPaths(); len(paths) != 1 {
+		t.Fatalf("backups = %v, want 1 generation within the interval", paths)
+	}
+}
+
+func TestLoadRecoversFromBackup(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SaveHistory(testItems("one", "two")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Backup(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.filePath, []byte("{garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	assertContents(t, mustLoad(t, s), "one", "two")
+}
+
+func TestLoadDamagedWithoutBackup(t *testing.T) {
+	s := newTestStorage(t)
+	if err := os.WriteFile(s.filePath, []byte("{garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadHistory(); err == nil {
+		t.Fatal("LoadHistory succeeded on a damaged file without backups")
+	}
+}
+
+func TestDamagedFileIsNotBackedUp(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SaveHistory(testItems("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Backup(); err != nil {
+		t.Fatal(err)
+	}
+	ageBackups(t, s)
+
+	if err := os.WriteFile(s.filePath, []byte("{garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Backup(); err == nil {
+		t.Fatal("Backup copied a damaged history file")
+	}
+	if paths := s.backupPaths(); len(paths) != 1 {
+		t.Fatalf("backups = %v, want the single good generation", paths)
+	}
+}
+
+func TestVerifyAndRepair(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SaveHistory(testItems("one", "two")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Backup(); err != nil {
+		t.Fatal(err)
+	}
+
+	report := s.Verify()
+	if !report.Healthy() {
+		t.Fatalf("fresh storage is not healthy: %+v", report)
+	}
+	if report.Primary.Items != 2 || len(report.Backups) != 1 {
+		t.Fatalf("report = %+v, want 2 items and 1 backup", report)
+	}
+
+	// Повреждённый основной файл и остаток прерванной записи
+	if err := os.WriteFile(s.filePath, []byte("{garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.filePath), tempPattern(s.filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp.Close()
+
+	report = s.Verify()
+	if report.Healthy() || report.Primary.Err == nil || len(report.TempFiles) != 1 {
+		t.Fatalf("damage not reported: %+v", report)
+	}
+
+	if err := s.Repair(); err != nil {
+		t.Fatal(err)
+	}
+	if report := s.Verify(); !report.Healthy() {
+		t.Fatalf("storage is not healthy after Repair: %+v", report)
+	}
+	assertContents(t, mustLoad(t, s), "one", "two")
+}
+
+func TestRepairWithoutBackup(t *testing.T) {
+	s := newTestStorage(t)
+	if err := os.WriteFile(s.filePath, []byte("{garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Repair(); err == nil {
+		t.Fatal("Repair succeeded without a valid backup")
+	}
+}
+
+func TestRekeyRemovesBackups(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.Unlock(Secret{Passphrase: []byte("old")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveHistory(testItems("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Backup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Rekey(Secret{Passphrase: []byte("new")}); err != nil {
+		t.Fatal(err)
+	}
+	if paths := s.backupPaths(); len(paths) != 0 {
+		t.Fatalf("backups encrypted with the old key were kept: %v", paths)
+	}
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// blobGracePeriod защищает от сборки мусора только что записанные блобы,
+// ссылка на которые ещё не попала в историю
+const blobGracePeriod = time.Hour
+
+var blobNamePattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// BlobStore хранит крупное содержимое в каталоге blobs рядом с историей.
+// Файл блоба называется SHA-256 содержимого и лежит в подкаталоге по первым
+// двум символам хеша. При включённом шифровании блобы шифруются тем же ключом.
+type BlobStore struct {
+	dir     string
+	storage *Storage
+}
+
+// Blobs возвращает хранилище блобов этого хранилища истории
+func (s *Storage) Blobs() *BlobStore {
+	return &BlobStore{
+		dir:     filepath.Join(filepath.Dir(s.filePath), "blobs"),
+		storage: s,
+	}
+}
+
+func (b *BlobStore) path(hash string) string {
+	return filepath.Join(b.dir, hash[:2], hash)
+}
+
+// Put сохраняет содержимое и возвращает его хеш. Повторное сохранение
+// того же содержимого не создаёт новый файл.
+func (b *BlobStore) Put(data []byte) (string, error) {
+	hash := types.ContentHash(data)
+	path := b.path(hash)
+
+	if _, err := os.Stat(path); err == nil {
+		// Обновляем время, чтобы блоб не попал под сборку мусора до записи ссылки
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+
+	enc := b.encryption()
+	if enc != nil {
+		sealed, err := enc.seal(data)
+		if err != nil {
+			return "", err
+		}
+		data = sealed
+	}
+
+	return hash, WriteFileAtomic(path, data, b.storage.fileMode())
+}
+
+// Get загружает содержимое блоба и проверяет, что оно соответствует хешу
+func (b *BlobStore) Get(hash string) ([]byte, error) {
+	if !blobNamePattern.MatchString(hash) {
+		return nil, fmt.Errorf("invalid blob hash %q", hash)
+	}
+
+	data, err := os.ReadFile(b.path(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = b.open(data)
+	if err != nil {
+		return nil, err
+	}
+	if types.ContentHash(data) != hash {
+		return nil, fmt.Errorf("blob %s is damaged", hash)
+	}
+	return data, nil
+}
+
+func (b *BlobStore) open(data []byte) ([]byte, error) {
+	if !isEncrypted(data) {
+		return data, nil
+	}
+	enc := b.encryption()
+	if enc == nil {
+		return nil, ErrLocked
+	}
+	return enc.open(data)
+}
+
+func (b *BlobStore) encryption() *encryption {
+	b.storage.mu.Lock()
+	defer b.storage.mu.Unlock()
+	return b.storage.encryption
+}
+
+// hashes возвращает хеши всех блобов на диске
+func (b *BlobStore) hashes() ([]string, error) {
+	var hashes []string
+
+	err := filepath.WalkDir(b.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() && blobNamePattern.MatchString(d.Name()) {
+			hashes = append(hashes, d.Name())
+		}
+		return nil
+	})
+
+	return hashes, err
+}
+
+// reencrypt перечитывает все блобы ключом old (nil — открытый текст)
+// и записывает их текущим ключом хранилища
+func (b *BlobStore) reencrypt(old *encryption) error {
+	hashes, err := b.hashes()
+	if err != nil {
+		return err
+	}
+
+	current := b.encryption()
+	for _, hash := range hashes {
+		path := b.path(hash)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if isEncrypted(data) {
+			if old == nil {
+				return ErrLocked
+			}
+			if data, err = old.open(data); err != nil {
+				return fmt.Errorf("blob %s: %w", hash, err)
+			}
+		}
+		if current != nil {
+			if data, err = current.seal(data); err != nil {
+				return err
+			}
+		}
+
+		if err := WriteFileAtomic(path, data, b.storage.fileMode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CollectGarbage удаляет блобы, на которые не ссылаются ни история,
+// ни её резервные копии. Возвращает число удалённых блобов.
+func (s *Storage) CollectGarbage() (int, error) {
+	history, err := s.LoadHistory()
+	if err != nil {
+		return 0, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, item := range history {
+		if item.BlobHash != "" {
+			referenced[item.BlobHash] = true
+		}
+	}
+
+	// Резервные копии тоже должны оставаться восстановимыми
+	for _, path := range s.backupPaths() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		backup, err := s.decode(data)
+		if err != nil {
+			continue
+		}
+		for _, item := range backup {
+			if item.BlobHash != "" {
+				referenced[item.BlobHash] = true
+			}
+		}
+	}
+
+	blobs := s.Blobs()
+	hashes, err := blobs.hashes()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, hash := range hashes {
+		if referenced[hash] {
+			continue
+		}
+		path := blobs.path(hash)
+		info, err := os.Stat(path)
+		if err != nil || time.Since(info.ModTime()) < blobGracePeriod {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		os.Remove(filepath.Dir(path)) // Удаляется, только если каталог пуст
+		removed++
+	}
+
+	return removed, nil
+}
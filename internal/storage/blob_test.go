@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// ageBlob сдвигает время изменения блоба за пределы защитного интервала
+func ageBlob(t *testing.T, b *BlobStore, hash string) {
+	t.Helper()
+	past := time.Now().Add(-2 * blobGracePeriod)
+	if err := os.Chtimes(b.path(hash), past, past); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func blobItem(hash string, size int) types.ClipboardItem {
+	return types.ClipboardItem{BlobHash: hash, Size: size, Kind: types.KindText, Timestamp: time.Now()}
+}
+
+func TestBlobPutGet(t *testing.T) {
+	b := newTestStorage(t).Blobs()
+	data := []byte("large clipboard content")
+
+	hash, err := b.Put(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != types.ContentHash(data) {
+		t.Fatalf("hash = %s, want the content hash", hash)
+	}
+	if again, err := b.Put(data); err != nil || again != hash {
+		t.Fatalf("second Put = %s, %v; want %s", again, err, hash)
+	}
+	if hashes, _ := b.hashes(); len(hashes) != 1 {
+		t.Fatalf("blobs on disk = %v, want one", hashes)
+	}
+
+	got, err := b.Get(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Get = %q, want %q", got, data)
+	}
+}
+
+func TestBlobGetRejectsBadInput(t *testing.T) {
+	b := newTestStorage(t).Blobs()
+	hash, err := b.Put([]byte("content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Get("../history.json"); err == nil {
+		t.Fatal("Get accepted a path instead of a hash")
+	}
+	if err := os.WriteFile(b.path(hash), []byte("damaged"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get(hash); err == nil {
+		t.Fatal("Get returned a blob that does not match its hash")
+	}
+}
+
+func TestEncryptedBlob(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.Unlock(Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+	b := s.Blobs()
+	hash, err := b.Put([]byte("secret blob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(b.path(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(raw) || bytes.Contains(raw, []byte("secret blob")) {
+		t.Fatal("blob was not encrypted")
+	}
+
+	locked, err := NewStorage(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locked.Blobs().Get(hash); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Get without a key: err = %v, want ErrLocked", err)
+	}
+	if got, err := b.Get(hash); err != nil || string(got) != "secret blob" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+}
+
+func TestCollectGarbage(t *testing.T) {
+	s := newTestStorage(t)
+	b := s.Blobs()
+
+	put := func(content string) string {
+		t.Helper()
+		hash, err := b.Put([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	referenced := put("referenced by the history")
+	orphan := put("no longer referenced")
+	fresh := put("just written")
+	for _, hash := range []string{referenced, orphan} {
+		ageBlob(t, b, hash)
+	}
+
+	if err := s.SaveHistory([]types.ClipboardItem{blobItem(referenced, 25)}); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := s.CollectGarbage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	if _, err := b.Get(orphan); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("orphan blob survived: err = %v", err)
+	}
+	for _, hash := range []string{referenced, fresh} {
+		if _, err := b.Get(hash); err != nil {
+			t.Fatalf("blob %s was collected: %v", hash, err)
+		}
+	}
+}
+
+func TestCollectGarbageKeepsBlobsOfBackups(t *testing.T) {
+	s := newTestStorage(t)
+	b := s.Blobs()
+	hash, err := b.Put([]byte("only in the backup"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ageBlob(t, b, hash)
+
+	if err := s.SaveHistory([]types.ClipboardItem{blobItem(hash, 18)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Backup(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveHistory(testItems("inline")); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed, err := s.CollectGarbage(); err != nil || removed != 0 {
+		t.Fatalf("CollectGarbage = %d, %v; want the backup's blob kept", removed, err)
+	}
+
+	s.removeBackups()
+	if removed, err := s.CollectGarbage(); err != nil || removed != 1 {
+		t.Fatalf("CollectGarbage without backups = %d, %v; want 1", removed, err)
+	}
+}
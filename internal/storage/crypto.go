@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Формат зашифрованного файла:
+// magic (8) | версия (1) | kdf (1) | соль (16) | nonce (12) | шифротекст AES-GCM
+const (
+	encryptedMagic   = "SCLIPENC"
+	encryptedVersion = 1
+
+	kdfScrypt  = 1
+	kdfKeyFile = 2
+
+	saltSize   = 16
+	keySize    = 32
+	headerSize = len(encryptedMagic) + 2 + saltSize
+)
+
+// Параметры scrypt, рекомендованные для интерактивного входа
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// ErrLocked возвращается при чтении зашифрованной истории без ключа
+	ErrLocked = errors.New("history is encrypted and storage is locked")
+	// ErrWrongKey возвращается, если ключ не подходит к файлу истории
+	ErrWrongKey = errors.New("wrong passphrase or key file")
+)
+
+// Secret описывает, откуда брать ключ шифрования: из пароля или из файла ключа
+type Secret struct {
+	Passphrase []byte
+	KeyFile    string
+}
+
+// IsZero сообщает, что секрет не задан
+func (s Secret) IsZero() bool {
+	return len(s.Passphrase) == 0 && s.KeyFile == ""
+}
+
+type encryption struct {
+	kdf  byte
+	salt []byte
+	key  []byte
+}
+
+// newEncryption получает ключ из секрета. Для пароля используется соль из
+// существующего файла, либо генерируется новая, если salt равен nil.
+func newEncryption(secret Secret, salt []byte) (*encryption, error) {
+	if salt == nil {
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	}
+
+	if secret.KeyFile != "" {
+		key, err := readKeyFile(secret.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &encryption{kdf: kdfKeyFile, salt: salt, key: key}, nil
+	}
+
+	if len(secret.Passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+
+	key, err := scrypt.Key(secret.Passphrase, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	return &encryption{kdf: kdfScrypt, salt: salt, key: key}, nil
+}
+
+// readKeyFile читает 32-байтовый ключ в двоичном или шестнадцатеричном виде
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	if len(data) == keySize {
+		return data, nil
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("key file %s must contain %d raw bytes or %d hex characters", path, keySize, keySize*2)
+	}
+	return key, nil
+}
+
+func (e *encryption) seal(plaintext []byte) ([]byte, error) {
+	gcm, err := e.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, headerSize+len(nonce))
+	header = append(header, encryptedMagic...)
+	header = append(header, encryptedVersion, e.kdf)
+	header = append(header, e.salt...)
+	header = append(header, nonce...)
+
+	// Заголовок аутентифицируется вместе с данными
+	return gcm.Seal(header, nonce, plaintext, header[:headerSize]), nil
+}
+
+func (e *encryption) open(data []byte) ([]byte, error) {
+	gcm, err := e.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerSize+gcm.NonceSize() {
+		return nil, errors.New("encrypted history file is truncated")
+	}
+
+	nonce := data[headerSize : headerSize+gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, data[headerSize+gcm.NonceSize():], data[:headerSize])
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plaintext, nil
+}
+
+func (e *encryption) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedMagic))
+}
+
+// parseHeader возвращает соль и способ получения ключа из заголовка файла
+func parseHeader(data []byte) (kdf byte, salt []byte, err error) {
+	if len(data) < headerSize {
+		return 0, nil, errors.New("encrypted history file is truncated")
+	}
+	if data[len(encryptedMagic)] != encryptedVersion {
+		return 0, nil, fmt.Errorf("unsupported encrypted history version %d", data[len(encryptedMagic)])
+	}
+
+	kdf = data[len(encryptedMagic)+1]
+	salt = append([]byte(nil), data[len(encryptedMagic)+2:headerSize]...)
+	return kdf, salt, nil
+}
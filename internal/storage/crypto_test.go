@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnlockEncryptsPlaintextHistory(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SaveHistory(testItems("secret text")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Unlock(Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(data) || bytes.Contains(data, []byte("secret text")) {
+		t.Fatal("history file was not encrypted")
+	}
+	if encrypted, _ := s.IsEncrypted(); !encrypted {
+		t.Fatal("IsEncrypted = false after Unlock")
+	}
+	assertContents(t, mustLoad(t, s), "secret text")
+}
+
+func TestEncryptedHistoryNeedsKey(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.Unlock(Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveHistory(testItems("one")); err != nil {
+		t.Fatal(err)
+	}
+
+	locked, err := NewStorage(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locked.LoadHistory(); !errors.Is(err, ErrLocked) {
+		t.Fatalf("LoadHistory without key: err = %v, want ErrLocked", err)
+	}
+	if err := locked.Unlock(Secret{Passphrase: []byte("wrong")}); !errors.Is(err, ErrWrongKey) {
+		t.Fatalf("Unlock with wrong passphrase: err = %v, want ErrWrongKey", err)
+	}
+	if err := locked.Unlock(Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+	assertContents(t, mustLoad(t, locked), "one")
+}
+
+func TestRekey(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.Unlock(Secret{Passphrase: []byte("old")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveHistory(testItems("one", "two")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Rekey(Secret{Passphrase: []byte("new")}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewStorage(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Unlock(Secret{Passphrase: []byte("old")}); !errors.Is(err, ErrWrongKey) {
+		t.Fatalf("Unlock with old passphrase: err = %v, want ErrWrongKey", err)
+	}
+	if err := reopened.Unlock(Secret{Passphrase: []byte("new")}); err != nil {
+		t.Fatal(err)
+	}
+	assertContents(t, mustLoad(t, reopened), "one", "two")
+}
+
+func TestCheckPassphrase(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.CheckPassphrase([]byte("pass")); err == nil {
+		t.Fatal("CheckPassphrase succeeded without encryption")
+	}
+
+	if err := s.Unlock(Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+	if !s.SupportsLock() {
+		t.Fatal("SupportsLock = false with a passphrase")
+	}
+	if err := s.CheckPassphrase([]byte("pass")); err != nil {
+		t.Fatalf("CheckPassphrase with the right passphrase: %v", err)
+	}
+	if err := s.CheckPassphrase([]byte("wrong")); !errors.Is(err, ErrWrongKey) {
+		t.Fatalf("CheckPassphrase with a wrong passphrase: err = %v, want ErrWrongKey", err)
+	}
+}
+
+func TestKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(bytes.Repeat([]byte{7}, keySize))+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestStorage(t)
+	if err := s.Unlock(Secret{KeyFile: keyFile}); err != nil {
+		t.Fatal(err)
+	}
+	if s.SupportsLock() {
+		t.Fatal("SupportsLock = true with a key file")
+	}
+	if err := s.SaveHistory(testItems("one")); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewStorage(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Unlock(Secret{KeyFile: keyFile}); err != nil {
+		t.Fatal(err)
+	}
+	assertContents(t, mustLoad(t, reopened), "one")
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// Форматы файла истории. Все они хранят версию схемы, чтобы новые поля
+// ClipboardItem не ломали старые установки:
+//
+//	json  — {"version": N, "engine": "json", "items": [...]} с отступами
+//	jsonl — строка-заголовок {"version": N, "engine": "jsonl"}, затем по элементу на строку
+//	gob   — магическая строка gobMagic, затем gob-кодированный gobEnvelope
+//
+// Файлы версии 1 — голый JSON-массив элементов без заголовка.
+const gobMagic = "SCLIPGOB"
+
+// envelope — заголовок файла истории в форматах json и jsonl
+type envelope struct {
+	Version int               `json:"version"`
+	Engine  string            `json:"engine,omitempty"`
+	Items   []json.RawMessage `json:"items,omitempty"`
+}
+
+type gobEnvelope struct {
+	Version int
+	Items   []types.ClipboardItem
+}
+
+// codec сериализует историю в одном из форматов
+type codec interface {
+	encode(history []types.ClipboardItem) ([]byte, error)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) encode(history []types.ClipboardItem) ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(history))
+	for _, item := range history {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, data)
+	}
+
+	return json.MarshalIndent(envelope{Version: SchemaVersion, Engine: EngineJSON, Items: items}, "", "  ")
+}
+
+type jsonlCodec struct{}
+
+func (jsonlCodec) encode(history []types.ClipboardItem) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+
+	if err := encoder.Encode(envelope{Version: SchemaVersion, Engine: EngineJSONL}); err != nil {
+		return nil, err
+	}
+	for _, item := range history {
+		if err := encoder.Encode(item); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+type gobCodec struct{}
+
+func (gobCodec) encode(history []types.ClipboardItem) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(gobMagic)
+	if err := gob.NewEncoder(&buf).Encode(gobEnvelope{Version: SchemaVersion, Items: history}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeAny разбирает файл истории в любом поддерживаемом формате
+// и приводит элементы к текущей версии схемы
+func decodeAny(data []byte) ([]types.ClipboardItem, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(data, []byte(gobMagic)):
+		return decodeGob(data[len(gobMagic):])
+	case len(trimmed) == 0:
+		return []types.ClipboardItem{}, nil
+	case trimmed[0] == '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		return migrateItems(1, items)
+	case trimmed[0] == '{':
+		var env envelope
+		if err := json.Unmarshal(trimmed, &env); err == nil {
+			return migrateItems(env.Version, env.Items)
+		}
+		return decodeJSONL(trimmed)
+	default:
+		return nil, errors.New("unknown history file format")
+	}
+}
+
+func decodeJSONL(data []byte) ([]types.ClipboardItem, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	if !scanner.Scan() {
+		return nil, errors.New("history file has no header")
+	}
+	var env envelope
+	if err := json.Unmarshal(scanner.Bytes(), &env); err != nil {
+		return nil, fmt.Errorf("invalid history header: %w", err)
+	}
+
+	var items []json.RawMessage
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		items = append(items, json.RawMessage(append([]byte(nil), line...)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return migrateItems(env.Version, items)
+}
+
+func decodeGob(data []byte) ([]types.ClipboardItem, error) {
+	var env gobEnvelope
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&env); err != nil {
+		return nil, err
+	}
+	if env.Version == SchemaVersion {
+		return env.Items, nil
+	}
+
+	// Старые версии проходят через те же миграции, что и JSON
+	items := make([]json.RawMessage, 0, len(env.Items))
+	for _, item := range env.Items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, raw)
+	}
+	return migrateItems(env.Version, items)
+}
@@ -0,0 +1,331 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// Журнал — файл рядом с историей, в который дописываются небольшие записи
+// об изменениях. При загрузке записи применяются поверх снимка (файла
+// истории), а периодическое сжатие записывает новый снимок и очищает журнал.
+const (
+	journalSuffix = ".journal"
+
+	// journalCompactRecords — число записей, после которого журнал сжимается сразу
+	journalCompactRecords = 500
+	// journalCompactDelay — сжатие откладывается, пока изменения продолжают поступать
+	journalCompactDelay = 30 * time.Second
+
+	// Префикс зашифрованной записи журнала
+	encryptedRecordPrefix = 'E'
+)
+
+// SetHistoryCallback задаёт функцию получения текущей истории для сжатия журнала
+// и сохранения без журнала
+func (s *Storage) SetHistoryCallback(callback func() []types.ClipboardItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.getHistoryFunc = callback
+}
+
+func (s *Storage) journalPath() string {
+	return s.filePath + journalSuffix
+}
+
+// Append сохраняет одно изменение истории. С журналом это дописывание одной
+// строки, без журнала — сохранение всей текущей истории.
+func (s *Storage) Append(change types.Change) error {
+	s.mu.Lock()
+	if !s.engine.journal {
+		getHistory := s.getHistoryFunc
+		s.mu.Unlock()
+		if getHistory != nil {
+			return s.SaveHistory(getHistory())
+		}
+
+		// Без менеджера истории (например, из командной строки)
+		history, err := s.LoadHistory()
+		if err != nil {
+			return err
+		}
+		return s.SaveHistory(applyChange(history, change))
+	}
+	defer s.mu.Unlock()
+
+	line, err := s.encodeRecord(change)
+	if err != nil {
+		return err
+	}
+
+	if s.journal == nil {
+		if err := s.openJournal(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.journal.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := s.journal.Sync(); err != nil {
+		return err
+	}
+	s.journalRecords++
+
+	s.scheduleCompaction()
+	return nil
+}
+
+// openJournal открывает журнал на дописывание. Если последняя запись была
+// оборвана сбоем, она отделяется переводом строки, чтобы не испортить новую.
+func (s *Storage) openJournal() error {
+	file, err := os.OpenFile(s.journalPath(), os.O_CREATE|os.O_RDWR|os.O_APPEND, s.fileMode())
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if info.Size() == 0 {
+		// Новый журнал начинается с заголовка с версией схемы
+		header, err := json.Marshal(envelope{Version: SchemaVersion, Engine: EngineJournal})
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := file.Write(append(header, '\n')); err != nil {
+			file.Close()
+			return err
+		}
+	} else {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := file.Write([]byte{'\n'}); err != nil {
+				file.Close()
+				return err
+			}
+		}
+	}
+
+	s.journal = file
+	return nil
+}
+
+// scheduleCompaction откладывает сжатие до паузы в изменениях,
+// но сжимает сразу, если журнал слишком вырос. Вызывается под s.mu.
+func (s *Storage) scheduleCompaction() {
+	if s.compactTimer != nil {
+		s.compactTimer.Stop()
+	}
+
+	delay := journalCompactDelay
+	if s.journalRecords >= journalCompactRecords {
+		delay = 0
+	}
+	s.compactTimer = time.AfterFunc(delay, func() {
+		if err := s.Compact(); err != nil {
+			logger.Warn("journal compaction failed", "err", err)
+		}
+	})
+}
+
+// Compact записывает текущую историю в файл истории и очищает журнал.
+// Если журнал пуст, ничего не делает.
+func (s *Storage) Compact() error {
+	s.mu.Lock()
+	getHistory := s.getHistoryFunc
+	pending := s.journalRecords
+	s.mu.Unlock()
+
+	if getHistory == nil || pending == 0 {
+		return nil
+	}
+	return s.SaveHistory(getHistory())
+}
+
+// truncateJournal очищает журнал после записи снимка. Вызывается под s.mu.
+func (s *Storage) truncateJournal() error {
+	if s.compactTimer != nil {
+		s.compactTimer.Stop()
+		s.compactTimer = nil
+	}
+	s.journalRecords = 0
+
+	if s.journal != nil {
+		s.journal.Close()
+		s.journal = nil
+	}
+
+	err := os.Remove(s.journalPath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// replayJournal применяет записи журнала к истории из снимка. Недописанная
+// при сбое последняя запись пропускается.
+func (s *Storage) replayJournal(history []types.ClipboardItem) ([]types.ClipboardItem, error) {
+	file, err := os.Open(s.journalPath())
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	records := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if isJournalHeader(line) {
+			var header envelope
+			if err := json.Unmarshal(line, &header); err == nil && header.Version > SchemaVersion {
+				return nil, ErrNewerSchema
+			}
+			continue
+		}
+
+		change, err := s.decodeRecord(line)
+		if err != nil {
+			if err == ErrLocked {
+				return nil, err
+			}
+			logger.Warn("skipping damaged journal record", "err", err)
+			continue
+		}
+
+		history = applyChange(history, change)
+		records++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	s.journalRecords = records
+	return history, nil
+}
+
+// isJournalHeader отличает заголовок журнала от записей изменений
+func isJournalHeader(line []byte) bool {
+	return bytes.HasPrefix(line, []byte(`{"version"`))
+}
+
+// applyChange применяет одно изменение к истории. Все операции идемпотентны,
+// поэтому повторное применение записей, уже попавших в снимок, безопасно.
+func applyChange(history []types.ClipboardItem, change types.Change) []types.ClipboardItem {
+	switch change.Op {
+	case types.OpAdd:
+		if change.Item == nil {
+			return history
+		}
+		history = removeContent(history, change.Item.ID())
+		return append([]types.ClipboardItem{*change.Item}, history...)
+	case types.OpUse:
+		for i := range history {
+			if history[i].ID() == change.Content {
+				history[i].ClickCount = change.ClickCount
+			}
+		}
+	case types.OpPin:
+		for i := range history {
+			if history[i].ID() == change.Content {
+				history[i].Pinned = change.Pinned
+			}
+		}
+	case types.OpTag:
+		for i := range history {
+			if history[i].ID() == change.Content {
+				history[i].Tags = change.Tags
+			}
+		}
+	case types.OpEdit:
+		if change.Item == nil {
+			return history
+		}
+		if change.Item.ID() != change.Content {
+			history = removeContent(history, change.Item.ID())
+		}
+		for i := range history {
+			if history[i].ID() == change.Content {
+				history[i] = *change.Item
+				return history
+			}
+		}
+		return append([]types.ClipboardItem{*change.Item}, history...)
+	case types.OpDelete:
+		return removeContent(history, change.Content)
+	case types.OpClear:
+		return []types.ClipboardItem{}
+	case types.OpReplace:
+		return append([]types.ClipboardItem{}, change.Items...)
+	}
+	return history
+}
+
+// removeContent удаляет элемент с указанным ID
+func removeContent(history []types.ClipboardItem, id string) []types.ClipboardItem {
+	for i, item := range history {
+		if item.ID() == id {
+			return append(history[:i:i], history[i+1:]...)
+		}
+	}
+	return history
+}
+
+// encodeRecord сериализует запись журнала; при включённом шифровании
+// запись шифруется целиком и кодируется в base64
+func (s *Storage) encodeRecord(change types.Change) ([]byte, error) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return nil, err
+	}
+	if s.encryption == nil {
+		return data, nil
+	}
+
+	sealed, err := s.encryption.seal(data)
+	if err != nil {
+		return nil, err
+	}
+	line := make([]byte, 1+base64.StdEncoding.EncodedLen(len(sealed)))
+	line[0] = encryptedRecordPrefix
+	base64.StdEncoding.Encode(line[1:], sealed)
+	return line, nil
+}
+
+func (s *Storage) decodeRecord(line []byte) (types.Change, error) {
+	var change types.Change
+
+	if line[0] == encryptedRecordPrefix {
+		if s.encryption == nil {
+			return change, ErrLocked
+		}
+		sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(line[1:])))
+		if err != nil {
+			return change, err
+		}
+		line, err = s.encryption.open(sealed)
+		if err != nil {
+			return change, err
+		}
+	}
+
+	err := json.Unmarshal(line, &change)
+	return change, err
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// newJournalStorage создаёт хранилище с журналом и закрывает журнал в конце теста
+func newJournalStorage(t *testing.T) *Storage {
+	t.Helper()
+	s := newTestStorage(t)
+	if err := s.SetEngine(EngineJournal); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.compactTimer != nil {
+			s.compactTimer.Stop()
+		}
+		if s.journal != nil {
+			s.journal.Close()
+		}
+	})
+	return s
+}
+
+func mustAppend(t *testing.T, s *Storage, change types.Change) {
+	t.Helper()
+	if err := s.Append(change); err != nil {
+		t.Fatalf("Append(%s): %v", change.Op, err)
+	}
+}
+
+func TestJournalReplay(t *testing.T) {
+	s := newJournalStorage(t)
+	if err := s.SaveHistory(testItems("one", "two")); err != nil {
+		t.Fatal(err)
+	}
+
+	three := testItems("three")[0]
+	mustAppend(t, s, types.Change{Op: types.OpAdd, Item: &three})
+	mustAppend(t, s, types.Change{Op: types.OpDelete, Content: "two"})
+	mustAppend(t, s, types.Change{Op: types.OpPin, Content: "one", Pinned: true})
+	mustAppend(t, s, types.Change{Op: types.OpTag, Content: "three", Tags: []string{"work"}})
+	mustAppend(t, s, types.Change{Op: types.OpUse, Content: "one", ClickCount: 4})
+
+	// Снимок не менялся, изменения есть только в журнале
+	snapshot, err := os.ReadFile(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := s.decode(snapshot); len(history) != 2 {
+		t.Fatalf("snapshot has %d items, want 2", len(history))
+	}
+
+	reopened, err := NewStorage(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.SetEngine(EngineJournal); err != nil {
+		t.Fatal(err)
+	}
+	history := mustLoad(t, reopened)
+	assertContents(t, history, "three", "one")
+	if len(history[0].Tags) != 1 || history[0].Tags[0] != "work" {
+		t.Fatalf("tags = %q, want [work]", history[0].Tags)
+	}
+	if !history[1].Pinned || history[1].ClickCount != 4 {
+		t.Fatalf("item = %+v, want pinned with 4 clicks", history[1])
+	}
+	if reopened.journalRecords != 5 {
+		t.Fatalf("journalRecords = %d, want 5", reopened.journalRecords)
+	}
+}
+
+func TestJournalEditAndReplace(t *testing.T) {
+	s := newJournalStorage(t)
+	if err := s.SaveHistory(testItems("one", "two")); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := testItems("edited")[0]
+	mustAppend(t, s, types.Change{Op: types.OpEdit, Content: "two", Item: &edited})
+	assertContents(t, mustLoad(t, s), "one", "edited")
+
+	mustAppend(t, s, types.Change{Op: types.OpReplace, Items: testItems("a", "b")})
+	assertContents(t, mustLoad(t, s), "a", "b")
+
+	mustAppend(t, s, types.Change{Op: types.OpClear})
+	assertContents(t, mustLoad(t, s))
+}
+
+func TestJournalSkipsTornRecord(t *testing.T) {
+	s := newJournalStorage(t)
+	one := testItems("one")[0]
+	mustAppend(t, s, types.Change{Op: types.OpAdd, Item: &one})
+
+	// Запись, оборванная сбоем посреди дописывания
+	if _, err := s.journal.WriteString(`{"op":"add","item":{"cont`); err != nil {
+		t.Fatal(err)
+	}
+	s.journal.Close()
+	s.journal = nil
+
+	assertContents(t, mustLoad(t, s), "one")
+
+	// Следующая запись начинается с новой строки и не теряется
+	two := testItems("two")[0]
+	mustAppend(t, s, types.Change{Op: types.OpAdd, Item: &two})
+	assertContents(t, mustLoad(t, s), "two", "one")
+}
+
+func TestJournalCompact(t *testing.T) {
+	s := newJournalStorage(t)
+	var history []types.ClipboardItem
+	s.SetHistoryCallback(func() []types.ClipboardItem { return history })
+
+	for _, item := range testItems("one", "two") {
+		history = append([]types.ClipboardItem{item}, history...)
+		mustAppend(t, s, types.Change{Op: types.OpAdd, Item: &item})
+	}
+	if _, err := os.Stat(s.journalPath()); err != nil {
+		t.Fatalf("journal was not written: %v", err)
+	}
+
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.journalPath()); !os.IsNotExist(err) {
+		t.Fatalf("journal was not removed after Compact: %v", err)
+	}
+	if s.journalRecords != 0 {
+		t.Fatalf("journalRecords = %d after Compact, want 0", s.journalRecords)
+	}
+	assertContents(t, mustLoad(t, s), "two", "one")
+}
+
+func TestEncryptedJournal(t *testing.T) {
+	s := newJournalStorage(t)
+	if err := s.Unlock(Secret{Passphrase: []byte("pass")}); err != nil {
+		t.Fatal(err)
+	}
+	item := testItems("journal secret")[0]
+	if err := s.Append(types.Change{Op: types.OpAdd, Item: &item}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(s.journalPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("journal secret")) {
+		t.Fatal("journal record was written in plaintext")
+	}
+	assertContents(t, mustLoad(t, s), "journal secret")
+}
+
+func TestJournalNewerSchema(t *testing.T) {
+	s := newJournalStorage(t)
+	if err := os.WriteFile(s.journalPath(), []byte(`{"version":99,"engine":"journal"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadHistory(); !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("err = %v, want ErrNewerSchema", err)
+	}
+}
+
+func TestAppendWithoutJournal(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SaveHistory(testItems("one")); err != nil {
+		t.Fatal(err)
+	}
+
+	two := testItems("two")[0]
+	mustAppend(t, s, types.Change{Op: types.OpAdd, Item: &two})
+	if _, err := os.Stat(s.journalPath()); !os.IsNotExist(err) {
+		t.Fatal("json engine wrote a journal")
+	}
+	assertContents(t, mustLoad(t, s), "two", "one")
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// SchemaVersion — текущая версия схемы файлов истории
+const SchemaVersion = 2
+
+// migration переводит один элемент из версии N в версию N+1.
+// Элемент передаётся как JSON-объект, чтобы миграции не зависели
+// от текущего вида структуры ClipboardItem.
+type migration func(item map[string]any) error
+
+// migrations[N] переводит элемент из версии N в N+1
+var migrations = map[int]migration{
+	1: migrateV1ToV2,
+}
+
+// migrateV1ToV2: в версии 1 (голый массив) не было вида содержимого
+func migrateV1ToV2(item map[string]any) error {
+	if _, ok := item["kind"]; !ok {
+		item["kind"] = types.KindText
+	}
+	return nil
+}
+
+// ErrNewerSchema возвращается для файлов, записанных более новой версией
+// программы. Такие файлы не перезаписываются, чтобы не потерять данные.
+var ErrNewerSchema = fmt.Errorf("history was written by a newer version of smart-clipboard (schema > %d)", SchemaVersion)
+
+// migrateItems приводит элементы из версии version к SchemaVersion
+func migrateItems(version int, raw []json.RawMessage) ([]types.ClipboardItem, error) {
+	if version > SchemaVersion {
+		return nil, ErrNewerSchema
+	}
+	if version < 1 {
+		return nil, fmt.Errorf("invalid history schema version %d", version)
+	}
+
+	history := make([]types.ClipboardItem, 0, len(raw))
+	for _, data := range raw {
+		if version < SchemaVersion {
+			var fields map[string]any
+			if err := json.Unmarshal(data, &fields); err != nil {
+				return nil, err
+			}
+			for v := version; v < SchemaVersion; v++ {
+				if err := migrations[v](fields); err != nil {
+					return nil, fmt.Errorf("failed to migrate history item from schema %d: %w", v, err)
+				}
+			}
+			var err error
+			data, err = json.Marshal(fields)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var item types.ClipboardItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		history = append(history, item)
+	}
+
+	return history, nil
+}
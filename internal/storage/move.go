@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// Path возвращает путь к файлу истории
+func (s *Storage) Path() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.filePath
+}
+
+// Move переносит историю в newPath: записывает снимок history на новое место,
+// переносит блобы и удаляет старый файл, журнал и резервные копии. Чужую
+// историю по новому пути не перезаписывает. При ошибке хранилище продолжает
+// работать со старым файлом.
+func (s *Storage) Move(newPath string, history []types.ClipboardItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if newPath == s.filePath {
+		return nil
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("%s already exists", newPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return err
+	}
+	data, err := s.encode(history)
+	if err != nil {
+		return err
+	}
+	if err := WriteFileAtomic(newPath, data, s.fileMode()); err != nil {
+		return err
+	}
+
+	oldBlobs := &BlobStore{dir: filepath.Join(filepath.Dir(s.filePath), "blobs"), storage: s}
+	newBlobs := filepath.Join(filepath.Dir(newPath), "blobs")
+	if oldBlobs.dir != newBlobs {
+		if err := moveBlobs(oldBlobs, newBlobs); err != nil {
+			os.Remove(newPath)
+			return fmt.Errorf("failed to move blobs: %w", err)
+		}
+	}
+
+	// Снимок уже содержит всё из журнала
+	if err := s.truncateJournal(); err != nil {
+		return err
+	}
+	s.removeBackups()
+	oldPath := s.filePath
+	s.filePath = newPath
+	if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// moveBlobs переносит блобы в каталог dir. Блобы называются по хешу
+// содержимого, поэтому уже существующие в dir файлы не перезаписываются.
+func moveBlobs(from *BlobStore, dir string) error {
+	hashes, err := from.hashes()
+	if err != nil {
+		return err
+	}
+
+	to := &BlobStore{dir: dir}
+	for _, hash := range hashes {
+		src, dst := from.path(hash), to.path(hash)
+		if _, err := os.Stat(dst); err == nil {
+			os.Remove(src)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return err
+		}
+		// Между файловыми системами переименование невозможно, тогда копируем
+		if err := os.Rename(src, dst); err == nil {
+			continue
+		}
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		if err := WriteFileAtomic(dst, data, from.storage.fileMode()); err != nil {
+			return err
+		}
+		os.Remove(src)
+	}
+	return nil
+}
@@ -1,16 +1,40 @@
 package storage
 
 import (
-	"encoding/json"
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
 	"github.com/yoshapihoff/smart-clipboard/internal/types"
 )
 
+var logger = logging.For("storage")
+
 type Storage struct {
-	filePath string
+	filePath    string
+	encryption  *encryption  // nil, если история хранится открытым текстом
+	backupCount atomic.Int32 // Меняется при перезагрузке настроек, читается без mu
+
+	mu     sync.Mutex
+	engine engine
+
+	// Журнал изменений (см. journal.go)
+	journal        *os.File
+	journalRecords int
+	compactTimer   *time.Timer
+	getHistoryFunc func() []types.ClipboardItem
+}
+
+// loadedFile — прочитанный и разобранный файл истории
+type loadedFile struct {
+	data    []byte
+	history []types.ClipboardItem
 }
 
 func NewStorage(filePath string) (*Storage, error) {
@@ -20,35 +44,233 @@ func NewStorage(filePath string) (*Storage, error) {
 		return nil, err
 	}
 
-	return &Storage{filePath: filePath}, nil
+	s := &Storage{
+		filePath: filePath,
+		engine:   engines[EngineJSON],
+	}
+	s.backupCount.Store(defaultBackupCount)
+	return s, nil
 }
 
+// SaveHistory записывает полный снимок истории и очищает журнал
 func (s *Storage) SaveHistory(history []types.ClipboardItem) error {
-	data, err := json.MarshalIndent(history, "", "  ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.encode(history)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(s.filePath, data, 0644)
+	// Ошибка резервного копирования не должна мешать сохранению
+	if err := s.rotateBackups(false); err != nil {
+		logger.Warn("backup failed", "err", err)
+	}
+
+	if err := WriteFileAtomic(s.filePath, data, s.fileMode()); err != nil {
+		return err
+	}
+	return s.truncateJournal()
 }
 
+// LoadHistory читает снимок истории и применяет к нему журнал. Если файл
+// истории повреждён, снимок загружается из самой новой исправной резервной копии.
 func (s *Storage) LoadHistory() ([]types.ClipboardItem, error) {
-	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
-		return []types.ClipboardItem{}, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.loadSnapshot()
+	if err != nil {
+		return nil, err
 	}
+	return s.replayJournal(history)
+}
 
+func (s *Storage) loadSnapshot() ([]types.ClipboardItem, error) {
 	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return []types.ClipboardItem{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.decode(data)
+	if err == nil {
+		return history, nil
+	}
+	if errors.Is(err, ErrLocked) || errors.Is(err, ErrNewerSchema) {
+		return nil, err
+	}
+
+	recovered, err := s.recoverFromBackup(err)
 	if err != nil {
 		return nil, err
 	}
+	return recovered.history, nil
+}
 
-	var history []types.ClipboardItem
-	err = json.Unmarshal(data, &history)
+// encode сериализует историю в формате движка и шифрует её, если шифрование включено
+func (s *Storage) encode(history []types.ClipboardItem) ([]byte, error) {
+	data, err := s.engine.codec.encode(history)
 	if err != nil {
 		return nil, err
 	}
 
-	return history, nil
+	if s.encryption == nil {
+		return data, nil
+	}
+	return s.encryption.seal(data)
+}
+
+// decode расшифровывает (при необходимости) и разбирает содержимое файла истории
+func (s *Storage) decode(data []byte) ([]types.ClipboardItem, error) {
+	if isEncrypted(data) {
+		if s.encryption == nil {
+			return nil, ErrLocked
+		}
+		var err error
+		data, err = s.encryption.open(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decodeAny(data)
+}
+
+// fileMode возвращает права файла истории: зашифрованный доступен только владельцу
+func (s *Storage) fileMode() os.FileMode {
+	if s.encryption != nil {
+		return 0600
+	}
+	return 0644
+}
+
+// IsEncrypted сообщает, зашифрован ли файл истории на диске
+func (s *Storage) IsEncrypted() (bool, error) {
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return isEncrypted(data), nil
+}
+
+// Unlock включает шифрование с ключом из secret. Зашифрованный файл
+// проверяется этим ключом, а файл в открытом виде сразу перешифровывается.
+func (s *Storage) Unlock(secret Secret) error {
+	data, err := os.ReadFile(s.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if isEncrypted(data) {
+		return s.unlockEncrypted(secret)
+	}
+
+	// Файла нет или он в открытом виде — переходим на шифрование
+	history, err := s.LoadHistory()
+	if err != nil {
+		return fmt.Errorf("failed to read plaintext history for migration: %w", err)
+	}
+
+	enc, err := newEncryption(secret, nil)
+	if err != nil {
+		return err
+	}
+	s.encryption = enc
+
+	if err := s.Blobs().reencrypt(nil); err != nil {
+		return fmt.Errorf("failed to encrypt blobs: %w", err)
+	}
+
+	if len(data) == 0 && len(history) == 0 {
+		return nil
+	}
+	if err := s.SaveHistory(history); err != nil {
+		return err
+	}
+	// Резервные копии в открытом виде больше не нужны
+	s.removeBackups()
+	return nil
+}
+
+// unlockEncrypted проверяет ключ на основном файле, а если он повреждён —
+// на резервных копиях
+func (s *Storage) unlockEncrypted(secret Secret) error {
+	lastErr := ErrWrongKey
+
+	for _, path := range append([]string{s.filePath}, s.backupPaths()...) {
+		data, err := os.ReadFile(path)
+		if err != nil || !isEncrypted(data) {
+			continue
+		}
+		_, salt, err := parseHeader(data)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		enc, err := newEncryption(secret, salt)
+		if err != nil {
+			return err
+		}
+		if _, err := enc.open(data); err != nil {
+			lastErr = err
+			continue
+		}
+		s.encryption = enc
+		return nil
+	}
+
+	return lastErr
+}
+
+// Rekey перешифровывает историю новым ключом. Зашифрованное хранилище должно
+// быть разблокировано, файл в открытом виде просто шифруется.
+func (s *Storage) Rekey(newSecret Secret) error {
+	history, err := s.LoadHistory()
+	if err != nil {
+		return err
+	}
+
+	enc, err := newEncryption(newSecret, nil)
+	if err != nil {
+		return err
+	}
+	old := s.encryption
+	s.encryption = enc
+
+	// Резервные копии зашифрованы старым ключом
+	s.removeBackups()
+	if err := s.SaveHistory(history); err != nil {
+		return err
+	}
+	return s.Blobs().reencrypt(old)
+}
+
+// SupportsLock сообщает, можно ли блокировать историю: для проверки при
+// разблокировке нужно шифрование на основе пароля
+func (s *Storage) SupportsLock() bool {
+	return s.encryption != nil && s.encryption.kdf == kdfScrypt
+}
+
+// CheckPassphrase проверяет, что пароль соответствует текущему ключу шифрования
+func (s *Storage) CheckPassphrase(passphrase []byte) error {
+	if !s.SupportsLock() {
+		return errors.New("history is not protected by a passphrase")
+	}
+
+	enc, err := newEncryption(Secret{Passphrase: passphrase}, s.encryption.salt)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(enc.key, s.encryption.key) != 1 {
+		return ErrWrongKey
+	}
+	return nil
 }
 
 func (s *Storage) CleanOldEntries(maxAge time.Duration) error {
@@ -61,7 +283,7 @@ func (s *Storage) CleanOldEntries(maxAge time.Duration) error {
 	cutoff := time.Now().Add(-maxAge)
 
 	for _, item := range history {
-		if item.Timestamp.After(cutoff) {
+		if item.Timestamp.After(cutoff) || item.Retained() {
 			filtered = append(filtered, item)
 		}
 	}
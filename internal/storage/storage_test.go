@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// newTestStorage создаёт хранилище во временном каталоге теста
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	s, err := NewStorage(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func testItems(contents ...string) []types.ClipboardItem {
+	items := make([]types.ClipboardItem, 0, len(contents))
+	for i, content := range contents {
+		items = append(items, types.ClipboardItem{
+			Content:   content,
+			Preview:   content,
+			Kind:      types.KindText,
+			Timestamp: time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC),
+		})
+	}
+	return items
+}
+
+func contents(history []types.ClipboardItem) []string {
+	result := make([]string, 0, len(history))
+	for _, item := range history {
+		result = append(result, item.Content)
+	}
+	return result
+}
+
+func mustLoad(t *testing.T, s *Storage) []types.ClipboardItem {
+	t.Helper()
+	history, err := s.LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	return history
+}
+
+func assertContents(t *testing.T, history []types.ClipboardItem, want ...string) {
+	t.Helper()
+	got := contents(history)
+	if len(got) != len(want) {
+		t.Fatalf("history = %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("history = %q, want %q", got, want)
+		}
+	}
+}
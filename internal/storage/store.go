@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+// Store — хранилище истории буфера обмена
+type Store interface {
+	// LoadHistory загружает всю историю
+	LoadHistory() ([]types.ClipboardItem, error)
+	// SaveHistory заменяет историю целиком
+	SaveHistory(history []types.ClipboardItem) error
+	// Append сохраняет одно изменение истории
+	Append(change types.Change) error
+	// Iterate вызывает fn для каждого элемента, пока fn возвращает true
+	Iterate(fn func(item types.ClipboardItem) bool) error
+	// Delete удаляет элемент с указанным содержимым
+	Delete(content string) error
+}
+
+var _ Store = (*Storage)(nil)
+
+// Движки хранения, выбираемые параметром storage_engine
+const (
+	EngineJSON    = "json"    // JSON с отступами, перезапись при каждом изменении
+	EngineJSONL   = "jsonl"   // компактный JSON, элемент на строку
+	EngineGob     = "gob"     // двоичный формат encoding/gob
+	EngineJournal = "journal" // снимок jsonl и журнал изменений
+)
+
+type engine struct {
+	codec   codec
+	journal bool
+}
+
+var engines = map[string]engine{
+	EngineJSON:    {codec: jsonCodec{}},
+	EngineJSONL:   {codec: jsonlCodec{}},
+	EngineGob:     {codec: gobCodec{}},
+	EngineJournal: {codec: jsonlCodec{}, journal: true},
+}
+
+// Engines возвращает имена поддерживаемых движков
+func Engines() []string {
+	return []string{EngineJSON, EngineJSONL, EngineGob, EngineJournal}
+}
+
+// SetEngine выбирает движок хранения. Файлы в любом из форматов читаются
+// всегда, поэтому при смене движка история переводится в новый формат
+// при следующем сохранении.
+// Пустое имя выбирает json — формат, совместимый с прежними версиями.
+func (s *Storage) SetEngine(name string) error {
+	if name == "" {
+		name = EngineJSON
+	}
+	e, ok := engines[name]
+	if !ok {
+		return fmt.Errorf("unknown storage engine %q", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.engine = e
+	return nil
+}
+
+// Iterate вызывает fn для каждого элемента истории
+func (s *Storage) Iterate(fn func(item types.ClipboardItem) bool) error {
+	history, err := s.LoadHistory()
+	if err != nil {
+		return err
+	}
+	for _, item := range history {
+		if !fn(item) {
+			break
+		}
+	}
+	return nil
+}
+
+// Delete удаляет элемент из истории. С журналом это одна запись журнала.
+func (s *Storage) Delete(content string) error {
+	s.mu.Lock()
+	journal := s.engine.journal
+	s.mu.Unlock()
+
+	if journal {
+		return s.Append(types.Change{Op: types.OpDelete, Content: content})
+	}
+
+	history, err := s.LoadHistory()
+	if err != nil {
+		return err
+	}
+	return s.SaveHistory(removeContent(history, content))
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/types"
+)
+
+func TestSaveLoadEngines(t *testing.T) {
+	for _, name := range Engines() {
+		t.Run(name, func(t *testing.T) {
+			s := newTestStorage(t)
+			if err := s.SetEngine(name); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SaveHistory(testItems("one", "two")); err != nil {
+				t.Fatal(err)
+			}
+			assertContents(t, mustLoad(t, s), "one", "two")
+		})
+	}
+}
+
+func TestSetEngineUnknown(t *testing.T) {
+	if err := newTestStorage(t).SetEngine("xml"); err == nil {
+		t.Fatal("SetEngine accepted an unknown engine")
+	}
+}
+
+func TestEngineSwitchConvertsFormat(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SetEngine(EngineGob); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveHistory(testItems("one")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Файл в формате gob читается при любом движке
+	if err := s.SetEngine(EngineJSONL); err != nil {
+		t.Fatal(err)
+	}
+	history := mustLoad(t, s)
+	assertContents(t, history, "one")
+	if err := s.SaveHistory(history); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != '{' {
+		t.Fatalf("history was not converted to jsonl: %q", data)
+	}
+	assertContents(t, mustLoad(t, s), "one")
+}
+
+func TestMigrateV1(t *testing.T) {
+	s := newTestStorage(t)
+	v1 := `[
+  {"content": "plain", "timestamp": "2024-01-01T00:00:00Z", "preview": "plain", "click_count": 2},
+  {"content": "https://example.com", "timestamp": "2024-01-01T00:01:00Z", "preview": "https://example.com", "kind": "url"}
+]`
+	if err := os.WriteFile(s.filePath, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history := mustLoad(t, s)
+	assertContents(t, history, "plain", "https://example.com")
+	if history[0].Kind != types.KindText || history[0].ClickCount != 2 {
+		t.Fatalf("migrated item = %+v, want kind text with 2 clicks", history[0])
+	}
+	if history[1].Kind != types.KindURL {
+		t.Fatalf("existing kind was overwritten: %q", history[1].Kind)
+	}
+
+	// После сохранения файл записывается в текущей версии схемы
+	if err := s.SaveHistory(history); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != '{' {
+		t.Fatalf("history was saved without a version header: %q", data[:20])
+	}
+	assertContents(t, mustLoad(t, s), "plain", "https://example.com")
+}
+
+func TestNewerSchemaIsNotOverwritten(t *testing.T) {
+	s := newTestStorage(t)
+	newer := []byte(`{"version": 99, "engine": "json", "items": []}`)
+	if err := os.WriteFile(s.filePath, newer, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.LoadHistory(); !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("err = %v, want ErrNewerSchema", err)
+	}
+	if err := s.Append(types.Change{Op: types.OpClear}); !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("Append: err = %v, want ErrNewerSchema", err)
+	}
+	if data, _ := os.ReadFile(s.filePath); string(data) != string(newer) {
+		t.Fatalf("newer history file was overwritten: %q", data)
+	}
+}
+
+func TestMigrateItemsInvalidVersion(t *testing.T) {
+	if _, err := migrateItems(0, nil); err == nil {
+		t.Fatal("migrateItems accepted schema version 0")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net"
 	"strconv"
 	"strings"
@@ -13,9 +12,12 @@ import (
 	"time"
 
 	"github.com/yoshapihoff/smart-clipboard/internal/constants"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
 	"github.com/yoshapihoff/smart-clipboard/internal/types"
 )
 
+var logger = logging.For("sync")
+
 type SyncData struct {
 	History []types.ClipboardItem `json:"history"`
 	Type    string                `json:"type"` // "history" or "request_history"
@@ -28,9 +30,15 @@ type SyncManager struct {
 	mu            sync.Mutex
 	historyChan   chan<- []types.ClipboardItem
 	stopChan      chan struct{}
+	stopOnce      sync.Once
+	wg            sync.WaitGroup // Receive and discovery loops
+	suspended     bool // While suspended, history is neither sent nor accepted
+	disabled      bool // Turned off in the config: no discovery and no history exchange
 
 	// Callback for getting current history
 	getHistoryFunc func() []types.ClipboardItem
+	// Callback for peer discovery, may be nil
+	peerFunc func(peer string, connected bool)
 }
 
 func NewSyncManager(historyChan chan<- []types.ClipboardItem) (*SyncManager, error) {
@@ -58,6 +66,7 @@ func NewSyncManager(historyChan chan<- []types.ClipboardItem) (*SyncManager, err
 
 	sm.broadcastConn, err = net.ListenUDP("udp", broadcastAddr)
 	if err != nil {
+		sm.conn.Close()
 		return nil, fmt.Errorf("failed to listen on broadcast UDP: %w", err)
 	}
 
@@ -69,19 +78,35 @@ func NewSyncManager(historyChan chan<- []types.ClipboardItem) (*SyncManager, err
 
 func (sm *SyncManager) discoverServers() {
 	// Start discovery loops
+	sm.wg.Add(3)
 	go sm.broadcastDiscovery()
 	go sm.listenForDiscovery()
 
 	go sm.receiveLoop()
 }
 
+// Stop closes the sync sockets and waits for the receive and discovery loops
+// to exit. It is safe to call more than once.
 func (sm *SyncManager) Stop() {
-	close(sm.stopChan)
-	if sm.conn != nil {
-		sm.conn.Close()
-	}
-	if sm.broadcastConn != nil {
-		sm.broadcastConn.Close()
+	sm.stopOnce.Do(func() {
+		close(sm.stopChan)
+		if sm.conn != nil {
+			sm.conn.Close()
+		}
+		if sm.broadcastConn != nil {
+			sm.broadcastConn.Close()
+		}
+		sm.wg.Wait()
+	})
+}
+
+// stopped reports whether Stop has been called
+func (sm *SyncManager) stopped() bool {
+	select {
+	case <-sm.stopChan:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -90,14 +115,72 @@ func (sm *SyncManager) SetHistoryCallback(callback func() []types.ClipboardItem)
 	sm.getHistoryFunc = callback
 }
 
+// SetPeerCallback sets the function called when a sync peer is discovered
+func (sm *SyncManager) SetPeerCallback(callback func(peer string, connected bool)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.peerFunc = callback
+}
+
+// SetSuspended stops (or resumes) sending and accepting history. Discovery
+// keeps running so peers are known immediately after resuming.
+func (sm *SyncManager) SetSuspended(suspended bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.suspended = suspended
+}
+
+// SetEnabled turns sync on or off at runtime. While disabled, the manager
+// neither announces itself nor answers discovery and exchanges no history;
+// the sockets stay open so sync can be turned back on without a restart.
+func (sm *SyncManager) SetEnabled(enabled bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.disabled = !enabled
+}
+
+func (sm *SyncManager) isEnabled() bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return !sm.disabled
+}
+
+func (sm *SyncManager) isSuspended() bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.suspended || sm.disabled
+}
+
+// Peers returns the addresses of discovered sync servers
+func (sm *SyncManager) Peers() []string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	peers := make([]string, 0, len(sm.serverAddrs))
+	for _, addr := range sm.serverAddrs {
+		peers = append(peers, addr.String())
+	}
+	return peers
+}
+
+// IsSuspended reports whether sync is suspended or disabled
+func (sm *SyncManager) IsSuspended() bool {
+	return sm.isSuspended()
+}
+
 func (sm *SyncManager) SendHistory(history []types.ClipboardItem) error {
 	sm.mu.Lock()
 	serverCount := len(sm.serverAddrs)
+	suspended := sm.suspended || sm.disabled
 	sm.mu.Unlock()
+
+	if suspended {
+		return nil
+	}
 	
-	log.Printf("SendHistory called with %d items, sending to %d servers", len(history), serverCount)
+	logger.Debug("sending history", "items", len(history), "peers", serverCount)
 	if serverCount == 0 {
-		log.Printf("No servers discovered, skipping history send")
+		logger.Debug("no peers discovered, history not sent")
 		return nil
 	}
 
@@ -117,24 +200,26 @@ func (sm *SyncManager) SendHistory(history []types.ClipboardItem) error {
 	for _, serverAddr := range sm.serverAddrs {
 		conn, err := net.DialUDP("udp", nil, serverAddr)
 		if err != nil {
-			log.Printf("Failed to dial UDP to %s: %v", serverAddr, err)
+			logger.Warn("failed to dial peer", "peer", serverAddr, "err", err)
 			continue
 		}
 
 		_, err = conn.Write(jsonData)
 		conn.Close()
 		if err != nil {
-			log.Printf("Failed to send data to %s: %v", serverAddr, err)
+			logger.Warn("failed to send history", "peer", serverAddr, "err", err)
 			continue
 		}
 
-		log.Printf("Sent %d history items to %s", len(history), serverAddr)
+		logger.Debug("history sent", "items", len(history), "peer", serverAddr)
 	}
 
 	return nil
 }
 
 func (sm *SyncManager) receiveLoop() {
+	defer sm.wg.Done()
+
 	if sm.conn == nil {
 		return
 	}
@@ -144,15 +229,25 @@ func (sm *SyncManager) receiveLoop() {
 	for {
 		n, addr, err := sm.conn.ReadFromUDP(buffer)
 		if err != nil {
-			log.Printf("Error reading from UDP: %v", err)
+			// The socket is closed by Stop
+			if sm.stopped() {
+				return
+			}
+			logger.Warn("failed to read sync socket", "err", err)
 			continue
 		}
 
-		go sm.processReceivedData(buffer[:n], addr)
+		// The buffer is reused by the next read
+		data := append([]byte(nil), buffer[:n]...)
+		go sm.processReceivedData(data, addr)
 	}
 }
 
 func (sm *SyncManager) processReceivedData(data []byte, addr *net.UDPAddr) {
+	if sm.isSuspended() {
+		return
+	}
+
 	var syncData SyncData
 
 	// Try JSON first
@@ -162,30 +257,37 @@ func (sm *SyncManager) processReceivedData(data []byte, addr *net.UDPAddr) {
 		decoder := gob.NewDecoder(bytes.NewReader(data))
 		err = decoder.Decode(&syncData)
 		if err != nil {
-			log.Printf("Failed to unmarshal data from %s: %v", addr, err)
+			logger.Warn("failed to decode sync data", "peer", addr, "err", err)
 			return
 		}
 	}
 
 	switch syncData.Type {
 	case "history":
-		log.Printf("Received %d history items from %s", len(syncData.History), addr)
+		logger.Debug("history received", "items", len(syncData.History), "peer", addr)
 		if sm.historyChan != nil {
-			sm.historyChan <- syncData.History
+			select {
+			case sm.historyChan <- syncData.History:
+			case <-sm.stopChan:
+			}
 		}
 	default:
-		log.Printf("Received unknown sync data type from %s: %s", addr, syncData.Type)
+		logger.Warn("unknown sync data type", "peer", addr, "type", syncData.Type)
 	}
 }
 
 func (sm *SyncManager) broadcastDiscovery() {
+	defer sm.wg.Done()
+
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			sm.sendDiscoveryBroadcast()
+			if sm.isEnabled() {
+				sm.sendDiscoveryBroadcast()
+			}
 		case <-sm.stopChan:
 			return
 		}
@@ -196,7 +298,7 @@ func (sm *SyncManager) sendDiscoveryBroadcast() {
 	// Get local network interfaces
 	interfaces, err := net.Interfaces()
 	if err != nil {
-		log.Printf("Failed to get network interfaces: %v", err)
+		logger.Warn("failed to list network interfaces", "err", err)
 		return
 	}
 
@@ -248,12 +350,14 @@ func (sm *SyncManager) sendDiscoveryBroadcast() {
 				continue
 			}
 
-			log.Printf("Sent discovery broadcast on %s", iface.Name)
+			logger.Debug("discovery broadcast sent", "interface", iface.Name)
 		}
 	}
 }
 
 func (sm *SyncManager) listenForDiscovery() {
+	defer sm.wg.Done()
+
 	if sm.broadcastConn == nil {
 		return
 	}
@@ -267,11 +371,18 @@ func (sm *SyncManager) listenForDiscovery() {
 		default:
 			n, addr, err := sm.broadcastConn.ReadFromUDP(buffer)
 			if err != nil {
+				if sm.stopped() {
+					return
+				}
+				continue
+			}
+
+			if !sm.isEnabled() {
 				continue
 			}
 
 			message := string(buffer[:n])
-			log.Printf("Received discovery message: %s from %s", message, addr)
+			logger.Debug("discovery message received", "message", message, "from", addr)
 			if strings.HasPrefix(message, constants.DiscoveryMagicHeader) {
 				// Extract port from message
 				parts := strings.Split(message, ":")
@@ -292,6 +403,7 @@ func (sm *SyncManager) listenForDiscovery() {
 
 				// Add to server list if not already present
 				sm.mu.Lock()
+				var notifyPeer func(peer string, connected bool)
 				found := false
 				for _, existing := range sm.serverAddrs {
 					if existing.IP.Equal(serverAddr.IP) && existing.Port == serverAddr.Port {
@@ -302,15 +414,22 @@ func (sm *SyncManager) listenForDiscovery() {
 
 				if !found {
 					sm.serverAddrs = append(sm.serverAddrs, serverAddr)
-					log.Printf("Discovered server at %s:%d, total servers: %d", serverAddr.IP, serverAddr.Port, len(sm.serverAddrs))
+					logger.Info("peer discovered", "peer", serverAddr, "peers", len(sm.serverAddrs))
+					notifyPeer = sm.peerFunc
 
 					// Immediately send our history to the discovered server
-					history := sm.getHistoryFunc()
-					go sm.sendHistoryToServer(serverAddr, history)
+					if !sm.suspended && !sm.disabled {
+						history := sm.getHistoryFunc()
+						go sm.sendHistoryToServer(serverAddr, history)
+					}
 				} else {
-					log.Printf("Server %s:%d already known", serverAddr.IP, serverAddr.Port)
+					logger.Debug("peer already known", "peer", serverAddr)
 				}
 				sm.mu.Unlock()
+
+				if notifyPeer != nil {
+					notifyPeer(serverAddr.String(), true)
+				}
 			}
 		}
 	}
@@ -324,22 +443,22 @@ func (sm *SyncManager) sendHistoryToServer(serverAddr *net.UDPAddr, history []ty
 	}
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		log.Printf("Failed to marshal history for server %s: %v", serverAddr, err)
+		logger.Error("failed to encode history", "peer", serverAddr, "err", err)
 		return
 	}
 
 	conn, err := net.DialUDP("udp", nil, serverAddr)
 	if err != nil {
-		log.Printf("Failed to dial UDP to %s: %v", serverAddr, err)
+		logger.Warn("failed to dial peer", "peer", serverAddr, "err", err)
 		return
 	}
 	defer conn.Close()
 
 	_, err = conn.Write(jsonData)
 	if err != nil {
-		log.Printf("Failed to send history to %s: %v", serverAddr, err)
+		logger.Warn("failed to send history", "peer", serverAddr, "err", err)
 		return
 	}
 
-	log.Printf("Sent %d history items to %s", len(history), serverAddr)
+	logger.Debug("history sent", "items", len(history), "peer", serverAddr)
 }
@@ -0,0 +1,61 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFDsStart — первый дескриптор, переданный активацией по сокету
+const listenFDsStart = 3
+
+// Listener — сокет, переданный активацией по сокету
+type Listener struct {
+	net.Listener
+	// Name — FileDescriptorName= из юнита сокета, по умолчанию "unknown"
+	Name string
+}
+
+// Listeners возвращает сокеты, переданные процессу активацией, в порядке
+// юнита сокета, или ничего, если процесс запущен напрямую. Переменные
+// активации удаляются из окружения, чтобы дочерние процессы не подхватили
+// сокеты.
+func Listeners() ([]Listener, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	listeners := make([]Listener, 0, count)
+	for i := 0; i < count; i++ {
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		// FileListener дублирует дескриптор, исходный больше не нужен
+		file := os.NewFile(uintptr(listenFDsStart+i), name)
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("socket %q from systemd: %w", name, err)
+		}
+		listeners = append(listeners, Listener{Listener: listener, Name: name})
+	}
+	return listeners, nil
+}
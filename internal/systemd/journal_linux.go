@@ -0,0 +1,102 @@
+//go:build linux
+// +build linux
+
+package systemd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const journalSocket = "/run/systemd/journal/socket"
+
+// JournalStream сообщает, подключён ли стандартный поток ошибок к журналу:
+// так запускаются службы systemd с StandardError=journal по умолчанию
+func JournalStream() bool {
+	stream := os.Getenv("JOURNAL_STREAM")
+	if stream == "" {
+		return false
+	}
+
+	info, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return stream == fmt.Sprintf("%d:%d", stat.Dev, stat.Ino)
+}
+
+// JournalWriter отправляет вывод в журнал по его собственному протоколу:
+// каждая строка — отдельная запись, приоритет и идентификатор syslog —
+// отдельные поля. Строка может начинаться с префикса приоритета sd-daemon,
+// например "<3>" (ошибка); строки без него записываются как PriInfo.
+type JournalWriter struct {
+	conn       *net.UnixConn
+	identifier string
+}
+
+// NewJournalWriter подключается к журналу. identifier записывается в
+// SYSLOG_IDENTIFIER каждой записи.
+func NewJournalWriter(identifier string) (*JournalWriter, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &JournalWriter{conn: conn, identifier: identifier}, nil
+}
+
+// Write записывает p по одной записи на строку. Строки, которые журнал
+// не принял, выводятся в стандартный поток ошибок.
+func (w *JournalWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		priority, message := splitPriority(line)
+		if err := w.Send(priority, message, nil); err != nil {
+			fmt.Fprintln(os.Stderr, line)
+		}
+	}
+	return len(p), nil
+}
+
+// Send записывает одну запись с дополнительными полями. Имена полей
+// состоят из заглавных латинских букв, цифр и подчёркиваний.
+func (w *JournalWriter) Send(priority Priority, message string, fields map[string]string) error {
+	var buf bytes.Buffer
+	writeJournalField(&buf, "MESSAGE", message)
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(int(priority)))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", w.identifier)
+	writeJournalField(&buf, "SYSLOG_PID", strconv.Itoa(os.Getpid()))
+	for name, value := range fields {
+		writeJournalField(&buf, name, value)
+	}
+
+	_, err := w.conn.Write(buf.Bytes())
+	return err
+}
+
+// Close закрывает подключение к журналу
+func (w *JournalWriter) Close() error {
+	return w.conn.Close()
+}
+
+// writeJournalField кодирует поле; значения с переводом строки передаются
+// в двоичном виде с длиной
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(buf, "%s=%s\n", name, value)
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
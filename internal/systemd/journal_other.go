@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+package systemd
+
+import "errors"
+
+// JournalStream возвращает false: журнал есть только в Linux
+func JournalStream() bool {
+	return false
+}
+
+// JournalWriter недоступен на этой платформе
+type JournalWriter struct{}
+
+// NewJournalWriter сообщает, что журнал недоступен
+func NewJournalWriter(identifier string) (*JournalWriter, error) {
+	return nil, errors.New("the journal is only available on Linux")
+}
+
+// Write отбрасывает p
+func (w *JournalWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Send ничего не делает
+func (w *JournalWriter) Send(priority Priority, message string, fields map[string]string) error {
+	return nil
+}
+
+// Close ничего не делает
+func (w *JournalWriter) Close() error {
+	return nil
+}
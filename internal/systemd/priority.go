@@ -0,0 +1,26 @@
+package systemd
+
+import "strings"
+
+// Priority — приоритет записи журнала в терминах syslog
+type Priority int
+
+// Приоритеты из syslog(3)
+const (
+	PriEmerg Priority = iota
+	PriAlert
+	PriCrit
+	PriErr
+	PriWarning
+	PriNotice
+	PriInfo
+	PriDebug
+)
+
+// splitPriority отделяет префикс приоритета вида "<3>" от строки журнала
+func splitPriority(line string) (Priority, string) {
+	if len(line) >= 3 && line[0] == '<' && line[2] == '>' && line[1] >= '0' && line[1] <= '7' {
+		return Priority(line[1] - '0'), strings.TrimPrefix(line[3:], " ")
+	}
+	return PriInfo, line
+}
@@ -0,0 +1,61 @@
+// Package systemd связывает демон с пользовательской службой systemd:
+// сообщает о готовности, состоянии и сигналах watchdog через сокет
+// уведомлений (sd_notify), принимает сокеты, переданные активацией по
+// сокету, и пишет записи в журнал. Вне systemd всё это ничего не делает.
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Состояния для уведомлений, см. sd_notify(3)
+const (
+	StateReady     = "READY=1"
+	StateReloading = "RELOADING=1"
+	StateStopping  = "STOPPING=1"
+	StateWatchdog  = "WATCHDOG=1"
+)
+
+// Status возвращает состояние, задающее строку статуса в "systemctl status"
+func Status(status string) string {
+	return "STATUS=" + status
+}
+
+// Notify отправляет состояния менеджеру служб. Если процесс запущен не
+// systemd с сокетом уведомлений, возвращает false без ошибки.
+func Notify(states ...string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+
+	// Имя, начинающееся с @, — абстрактный сокет, net обрабатывает его сам
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval возвращает тайм-аут watchdog, заданный процессу через
+// WatchdogSec=, или 0, если watchdog выключен. Служба должна отправлять
+// StateWatchdog чаще, обычно вдвое.
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
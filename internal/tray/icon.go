@@ -0,0 +1,9 @@
+package tray
+
+import "encoding/base64"
+
+// Icon возвращает значок приложения, PNG 256x256 (assets/icon.png)
+func Icon() []byte {
+	icon, _ := base64.StdEncoding.DecodeString(iconBase64)
+	return icon
+}
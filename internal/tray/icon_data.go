@@ -1,3 +1,5 @@
 package tray
 
 const iconBase64 = "iVBORw0KGgoAAAANSUhEUgAAAQAAAAEACAQAAAD2e2DtAAAAwnpUWHRSYXcgcHJvZmlsZSB0eXBlIGV4aWYAAHjabVDbEcMgDPv3FB3BL4gZhzTpXTfo+LVj0gttdYesIFCMYX89H3ALMCloWay2WtGhTRt3F4aJfjChHnyCBk/78JHsVbxKGlbHrXOfphik7qpcguw+jHU2mmZl+wriLBIdhd5GUBtBwmnQCOj5LKzNlusT1h1nWC4IUpvb/vlefHpb8f8I8y4k6Cxi2YDEKiA9DOc45A1Lc13c6qLHUcqB/JvTCXgD155ZE2QDNKoAAAEjaUNDUElDQyBwcm9maWxlAAB4nJ2QsUrDUBSGv0ZRKeqiOIhCBtdOYicHq0IoKMRaweiU3rRYTGJIUopv4Jvow3QQBN/BVcHZ/0YHB7N44fB/HM75/3svOG5skmL+AJK0zL1eJ7gMrtzFN5o4rLLLdmiKrOP7J9Sez1caVl9a1qt+7s+zEA0LI52pUpPlJTT2xe1pmVlWsX7b7x2JH8RulKSR+Em8EyWRZbvbS+KJ+fG0t1kephfntq/awqPLKT4uAyaMiSlpSVN1jmmzJ/XICbmnwEhjhupNNVNyIyrk5HEo6ot0m5q8zSrPV8pAHmN52YQ7EnnaPOz/fq99nFWbjY1ZFuZh1ZpTOaMRvD/CSgBrz9C8rsla+v22mpl2NfPPN34BJiNQg3oqJ/AAAA16aVRYdFhNTDpjb20uYWRvYmUueG1wAAAAAAA8P3hwYWNrZXQgYmVnaW49Iu+7vyIgaWQ9Ilc1TTBNcENlaGlIenJlU3pOVGN6a2M5ZCI/Pgo8eDp4bXBtZXRhIHhtbG5zOng9ImFkb2JlOm5zOm1ldGEvIiB4OnhtcHRrPSJYTVAgQ29yZSA0LjQuMC1FeGl2MiI+CiA8cmRmOlJERiB4bWxuczpyZGY9Imh0dHA6Ly93d3cudzMub3JnLzE5OTkvMDIvMjItcmRmLXN5bnRheC1ucyMiPgogIDxyZGY6RGVzY3JpcHRpb24gcmRmOmFib3V0PSIiCiAgICB4bWxuczp4bXBNTT0iaHR0cDovL25zLmFkb2JlLmNvbS94YXAvMS4wL21tLyIKICAgIHhtbG5zOnN0RXZ0PSJodHRwOi8vbnMuYWRvYmUuY29tL3hhcC8xLjAvc1R5cGUvUmVzb3VyY2VFdmVudCMiCiAgICB4bWxuczpHSU1QPSJodHRwOi8vd3d3LmdpbXAub3JnL3htcC8iCiAgICB4bWxuczpkYz0iaHR0cDovL3B1cmwub3JnL2RjL2VsZW1lbnRzLzEuMS8iCiAgICB4bWxuczp0aWZmPSJodHRwOi8vbnMuYWRvYmUuY29tL3RpZmYvMS4wLyIKICAgIHhtbG5zOnhtcD0iaHR0cDovL25zLmFkb2JlLmNvbS94YXAvMS4wLyIKICAgeG1wTU06RG9jdW1lbnRJRD0iZ2ltcDpkb2NpZDpnaW1wOmM1NzM4ZGU1LWQwMDYtNGExNy04OGNlLWFjZDRmODUwZDdkMSIKICAgeG1wTU06SW5zdGFuY2VJRD0ieG1wLmlpZDpiZDFmMTZmNS1kNzBlLTRhMTQtYTFmMi1lMjkwZDVkZTA5MDEiCiAgIHhtcE1NOk9yaWdpbmFsRG9jdW1lbnRJRD0ieG1wLmRpZDpkOTI2MjNkZS1mOGZhLTQ0YTUtYTczYS02ZGRjZDg5MjNiMzUiCiAgIEdJTVA6QVBJPSIyLjAiCiAgIEdJTVA6UGxhdGZvcm09Ik1hYyBPUyIKICAgR0lNUDpUaW1lU3RhbXA9IjE3NTk0MTY3Njg3ODU0NzMiCiAgIEdJTVA6VmVyc2lvbj0iMi4xMC4zOCIKICAgZGM6Rm9ybWF0PSJpbWFnZS9wbmciCiAgIHRpZmY6T3JpZW50YXRpb249IjEiCiAgIHhtcDpDcmVhdG9yVG9vbD0iR0lNUCAyLjEwIgogICB4bXA6TWV0YWRhdGFEYXRlPSIyMDI1OjEwOjAyVDE5OjUyOjQ4KzA1OjAwIgogICB4bXA6TW9kaWZ5RGF0ZT0iMjAyNToxMDowMlQxOTo1Mjo0OCswNTowMCI+CiAgIDx4bXBNTTpIaXN0b3J5PgogICAgPHJkZjpTZXE+CiAgICAgPHJkZjpsaQogICAgICBzdEV2dDphY3Rpb249InNhdmVkIgogICAgICBzdEV2dDpjaGFuZ2VkPSIvIgogICAgICBzdEV2dDppbnN0YW5jZUlEPSJ4bXAuaWlkOmM5NzIzN2U1LWEwNDEtNDVkOC1hODcxLTk1MmMyNjg4M2E4MyIKICAgICAgc3RFdnQ6c29mdHdhcmVBZ2VudD0iR2ltcCAyLjEwIChNYWMgT1MpIgogICAgICBzdEV2dDp3aGVuPSIyMDI1LTEwLTAyVDE5OjUyOjQ4KzA1OjAwIi8+CiAgICA8L3JkZjpTZXE+CiAgIDwveG1wTU06SGlzdG9yeT4KICA8L3JkZjpEZXNjcmlwdGlvbj4KIDwvcmRmOlJERj4KPC94OnhtcG1ldGE+CiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgCiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgCiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgCiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgCiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgCiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgCiAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAKICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIAogICAgICAgICAgICAgICAgICAgICAgICAgICAKPD94cGFja2V0IGVuZD0idyI/PnjZhaUAAAACYktHRAAAqo0jMgAAAAlwSFlzAAALEwAACxMBAJqcGAAAAAd0SU1FB+kKAg40MIpL3pEAAAzrSURBVHja7Z1xaF3VHcc/tQ98jPyRP/LHgz0wYqYRg4ssYxXr9pwpxpmuVePWsg5btEyhxYZZurKF/qRKlVZaacEOK7a00gwrzTClESM+Z8AMM6w0bOkImI0U3lj+CFv+eMIb7I91Q2djz33v3vvOvff7Kf0r59573vl97jnnnnvOuStIHdZGBx3cQgcFoP1/f5inxjyXmGWWGVtCACtSFPhWStxLL50OiWtMUeZ9JrIuQioEsDwDPEpvHYdWOcPrjFtNAiQ1+N08xQAtDZ2kwilesnkJkLTgl9hDKaST1TjFCzYjAZIS/F720x36ac+w0+YkgO/BL7KfDRGdvMoBnrOqBPA1+Dl2sKfBNv9azLHdRiWAj+EvcLKu3n5wDrEzC88GiRLAejlJIbbLTbHRZtMuwHUJCv8OzscYfujhY+uVAL6E/yAHycV80RbO26Z0C7AyGR2/0us83qQb5KHSZ+UJCdDcfv9pftTEDPSWVpTLagKax2EGmpyDPbYhrQJ4/xRg2zjsQTaWuMemJED84e/nbOxdv6tT4Q6rSIB4w1/kIq3eZGeSe9I3ROx3H+CwR+GHVbyiGiDO+389Z+s+uMoYv6fCHAtUWKRIgQIFbqK3oXeIQ/asBIgn/K1cpFjHgQuMcI6x5StrK9LHOnrJ13H2GnemqzPorwD7ebqOjtpuTrm9wrECu9hWRwdz0u6UANGHv8CnAe/QRZ7haLBOmnXwSzYHztxWOyYBohbgIDsCHXCU3bZY15W6OUlXwEbmG/Vdy0dWenr/nwpQOdd4yvaU63xAK1dKJ7mdmwMc8jU+S8/QsJ+PgbsCVP9LrLUjDem2ZGt5JtAhT1mLaoDo7v88p5wFmOdum2z8muVy6RLrnW+HPH8vT6oGiIr1zsM/S6wNayK3DfNkkDrKchIgKh5zTrndLoRY8xzDvSkp0C8BomkA2p0nfY7b8ZAvPsi4c9qfSIBocH0urwaqst3kq/EIc46J+61VAkTBw65P/lHM2LVFdjt3BAckQBQjAG6DMjVeiigHw7iO9T8gAcKnzzHdcIQr+HY6pitJgPC5zzHdryOshcqMOSVstW4JEDZuTwBzFu1E7RezUwd4JYB10eaU8ETEGSnj9rLnLgkQLt3OAYpWxBpua4M7JUC4fMtxBCD6cfhzEqAZ9Dilmoxhbu4oLtfIWacEiL8JeD+G3sgSbsNMHRIgvEIvOO77UY4lOwtOqdokQHi0e9MDABzfCeQkQHi4TQK/ENPqnEqIeZYAIdYAca3P+xuZwCcBbgixbW4ct6Ggr0uA8HDrAl72qvzUB4idTO7omw0B3B6pFhS0bDcBiwpatpsAIQGEBBASQEgAIQGEBBASQEgAIQGEBBASQEgAIQEaZkECZJuMfFY+l7YfZF10Msu0Dx99tCI93EWVPzDt6xcIUySADfAzVl2ZVlK1aV61o03MTT+HPz/P2eZ50sfP0aZEAGvj5S/s2ZOnhx77MVubcedZKwe/tNlVkbfsOIO+7TLsUx+g7hn/too/XXXLphIXrb8J4f94mb3ONvOB5SXActS54sfyvLbshNI8r1nc6/cOfsUSly72SoCw2fOV6/TbeDnmtn/zVyZ42koSIMwC77jml0UGYv0E9MFrptgnAcKk16Ej+70Y2/9r7xjQ7dNG08kX4DaHNF2x5cZli4t8jPnJgAAuhdnjVW6gWwKER7tDmqLFtY6/LbQ8S4BQySEyLYCQAEICCAkgJECYtEoACSABhAQQEkBIgDjIK2jZFqCgoKkJEBJASAAhAYQE8IsFCZBtliRA3FRlXbYFqCiIagKEBBASQEiAEKlJgGwzLwGEBBASQEgAIQHiQGN6GRfgM4VDTYCQAEICpJ3rJUC2KUgAIQGEBBASIHutrgRoBlpSqiZASAAhAUKmXQIICSAkgJAAQgLoiV4CRI7G9NQECAkgJICXaIuYjKMtYoQEEBJASAAhAWKhpiBmW4B5BVFNgJAAobMgATKNLUkAIQGEBBASQEiAONAmURkXQJ9+UBOQEfISINtoixghAYQEEBJASAAhAYQEEBIgStoU6mwL0BLReVslQLaRAEICCAkgJICQALFwvYKWbQG0SZSaAI/RFjEZR1vECAkgJICQAEICCAkgJICQAEICCAkQDX9xShXVzJ0FCdBs3HYVuCHoaa3olGxeAiRDgI7A5y2EeHUJ0HQBei3ovOA+1QBpEiDP+oDnfcAp1WUJ0GRszjHhzy0X4KyrWaUawM97+cvMOqXqZrNz+HMcdEw6U1eOtUXMVal3k6hRx3T7rNMx5dP0uD0E2kRdOdYWMaHyW8d0bbxlDkVvG9jneMaR5BdeGgSYcB6O6eAdu8YDoW3iZOjqSYBIu4E1xpwTd/GRDSx7przt5ySuncUlxpNfejnSwDk2Oadt5Q2bYohxq30x+GxgX6DWecyqEsAPRqgECl0P51mwET6hwiJF2vkOpcB989fTUHSpEMCq9hyHAx7UxuMNXXTKRtJQdml5HXzUcTQgPHamo+BSIoDV2B1vo2NlCeCXAmeYiu1icesmAZwYjO0jksdsRgL4VwdMMBTLhWZiuo4ECKzA8xyP/CILPGgLEsBXtjIR6fmrPJie6j+FAliNR5iL8AJbbCJdJZa6aeFWYW1kM/WGbDht5ZXCdQE2zR0RPBLW2GnPpq+0kidA3qkWuJtToV61who7ABIgStye4p1e+ljVfsru0MYFJrgj1JG/qgS4GiFPsLTneTCENwQ1DnGPhdurqEiA+gmw8buNcitbGijuGse51QbNvSZpVxMQ9X0RaIGH1ew4NzLIYp3B32LB6hC3OQVqAq7ebjslK1ou6HntEDeynTHngp/hQB3BB9claB41AX5NCFlyuL9zdDIdWK5FjnDEWiixjj6Ky16/zDnGnBebfJmbHH+nBLgqs3Q7pKpDgCsaLDHKKFg7hSv/bgAWuUyFeeaoNDzLz60GuCQBlqt6XQT4JmcabGzmIhsu7nYUXX2ABu6MPjzFuik6ii4BGiiYHsfNG+LHbQVytYE+hgQIUNDxsy5pDYBvAsw6Dt6u8zH6VnTsAUxJgOV76W6FUzIfP9fW75jufQmwPG87Prv0eyjAw47pyhKg8cLxrhGwVla79XN86gL6J8Ck43DteuvwLOe7HN8DlP3KtmcCWJVJx0Zgr1f5LrAjiT0AH18H/8Yx3Qbr8SjX+xzv/yXfdhXxT4Bh53d2+725/zud9yc449ueAt4JYIvO90jJ+ry5/13fqXi3p4CPM4JOuBe8efAyy1Y7j0zO27gEuDbjzrMDuxvc5CGM8Oed9xQk5JnKaRXAarzqnHh/07uCL+OagyC/K9M1ABxynjPT4rb3X2SybnPff5RTNisBXDuCx5wTFzhrTdp61UoBqn94wcey9nVa+AsBZs6u4pWmhL/I6QAzqkb8XFW80s/4l5dKbY77dQPcXvpX+Xexd/7e5uYAB2wsV1QDBOHFQLPn99qmWMPfwhsE6X6esQt+FvNKX+Nf/kdpBd8PcMBDpVz5vZjCX+BdvhvggCrryot+lvMKb2sALM/FgN/6GWWjRT7n3ro5H3DD9yF/F5Z7vDbQqmwPeEg/H1p7xLnq54OA4Z/F44XlXi8OtbHA7866+NBWR5ijbZwl6Oentvu8qfQKvMaKfBxkPTAANZ7jQPhNgXWxv441Ccdsq88l7LkAYH2cr+OwCkMct9A2jrQCe9lcxzqqGb5tSz6X70rfBSjPlvIEr9Rb+CEPlT4thzD4avnSrzjNqjqayyr321/9Ll/vawCwHO9Rb7s+zqBNN3DtFjawt+6PPA3aId9LNwECgBX5qIEvbV3gTUaDDsRYC+t5mL4GPvE2bBv9L9tECADWw3uBe9///zA2yptMXrtfEELoASZYk4RPyiREALAS74SwmH2BCWb4hBkWbP4LQW+jQA+30E1PCB92nOZOvzt/iRMAbCDQ2ze3B8b/SFAM+byz3G0J+a74yuQIUP5j6Z8h7w1wHa200hrycFiF+/xa/ZMSAaA8WbrMDzzf2m6GNfbn5JTpChJGBA1BmEyzJimVf0IFAFvN+QafCKJigvuT0fX7fCuYPAEmWBP2trKhMJy88CeyBgCwNk56tVlUjUE7ksSSTKgAAPYL9nrSG5jjEZtKZikmWACw1Zym+TuGjbDFFpNahokWAKyFPexoYj0wz6CdSXIJJlwAAOvilQBTyMNs948ylNx7PzUCANgT7CHeJWJTbLfJ5JdcSgQAy/MEu2KS4ALPpOPj8SkSIDYJUhT81AlwRYJNPBZJn6DKCCdsLF3llToBrmjQyaNsDrEumOQEw0nv8GVIAADLUeI++uhq6K4v8y4jPq7slwCuIhTo5V566AwwXrDINBO8zWQSpnVJANcaoZNOuriNNlqu/P8vc8AcNWa5xAwXkvVKtxH+DUNe/8577851AAAAAElFTkSuQmCC"
+
+const iconPausedBase64 = "iVBORw0KGgoAAAANSUhEUgAAAQAAAAEACAYAAABccqhmAAAP8ElEQVR42uydYY8d1X2HH8zGcbebxTGOs3UdsjGLAeM4hiJKIKnHQhZCEaJuhBCKUISqKIr6ol/h9x36qopQlKZVhCitKhRRZCEPgRBCHWKM67jGcc3KcYyzOGbZOtZq2VauxooxNqx3Z+7O3Ps80tXyAp+Z+Z//eeacmTPnDCGtJ8kwsKb6XV/9HQEAVn/4XzANzFd/3wFOV7+pJLNGVC5wjSFoZYNfBYwDXwA2AmtrKnoeOAEcA94CJhWCApB2NPohYDPwJWBjjw47BxwE3gCOJpm3JhSA9LbhjwF/DmwGVi7jqcwA+4GfJZm2ZhSANNvwx4HtwHjLTm0e2A/8JMmUNaUApN6GvxHYCYx14HQPAruTnLHmFIAsreGPAjuBLR079TngZeDFJHPWpAKQq2v4K4C7ge3LPMZfKmeAZ5MctlYVgCys8Y8Au3r4VL8XvFINC3xjoADkIxr/RmAXMNKHl3cCeDrJaWu621xrCBpp/HcDfwl8sk8v8VPA7UVRnCjL8nfWuAKQPzT++4FiAHpX1wJfLIriTFmWb1vz3cQhQL0P+3Z18Cl/HTyf5CWzoJsWl3oa/9eB2wY0BBuLorimLMtjZkO3WGEIauEBYPOAx2B7ki2mAtgDGCCS3AVsNxIATBRFcbQsy/cMhQIYhMa/CXjIZykfyKebi6J4oyxLPzNWAH0/tfcx4BNG4wOsBG6oJOBkIfAZQB+P+1cZhsuyAXjQMLQfu66LIMktwCPLcOg54Ajwa2AGOAOcrf77HDAKjFz0+zSwcRm/PNyT5MdmjALop8a/CvgOMNqjQ54FDgFvAkcW80VeNVyZAG4GNgK9WgtyHngiyQkzp524KOjV89UeNf4Z4Hlg/1I/vEkyDbx2/pdkBLgXuKsHQ8AVwAPAE6YN2APoOFXj+duGxXkOeAHY2+T390nWAF8FtvUgdM8kec0MUgD9MM//7gYPsbeaVnuuh9c0BuwC1jU8jPm7Xl6XgK8B67/7/1VD3eZ54N+TlGVZ9nTVnbIsZ4qi2A98Fri+ocN8AnjfqcIKoLMURbEDuKGBomeBJ5O8sVzXVpbl+cZ54Px8/gYXKR0riuI/zh/LbGoPzgNY+Jr9TYyVp4HvJjnSkussgaeBJibwrALuMJsUQBe5pYFJP7PAD9u29HaSA8CPGir+3iTmnALoHLc3UOazSU628WKrJ/avNlD0CLDJdFIAnSHJ6gYW9TyaZF/LL/054GgD5X7RrFIAXaLusf9cg13s2kgyDzwF1L0pyKYkfkOhADrDrTWXt7crq+lW7+2fr7nYIRdPUQB0pAGM1DxBZh74WcdicACoey7/TWaXAugCEzWXd6Cj++ztrrm8cVNLAXSBG2su7+ddDEKSY0CdcxVWVVOQRQG0mjqf/p9JMtnhWPwU7AUogAEhyTpguMYiX+94SI4BdX7M8zmzTAG0mbEGGlBnSTIP1Lkz8FpTTAG0mT+p+d3/8T6IyZsKQAEMCutrLOt4k4t79JDDQF3XsSKJElAAAzEEeKsfApJkFqhzEtMa00wBtHUC0Epw/H+F1X3qYthsUwBtZHWNZfXL+P8CZ8w/BdDv1Lnq78k+Gf9fvFpxG+MsCqCVPYCZPovN/5geCqDfua6lY+Y2UOdkoE+ZagqgjdT5ANCtss0/K2CAmTYEogC6xTAOAUQBgEOAVo2ZRRSAiCgAEVEAIqIAREQBiIgCEBEFICIKQEQUgIgoABFRACKiAEQUgIgoABHw82YFIHJFZg1B/zBkCDqxSela4DRwKsm8UYEko8B64HPAHPCbKj6njY4C6DRJNgN/Bmy4ZHGSuSSngF8k2TugsdkEPHCllZuTTAM/SnLYTFIAnSLJMPA1YPNH1Nf6878ktwHPDModL8kq4H5g28f8r6PAo0n2Ac8lcUUm8BlA29fyT7IB+JuPaPyXMg58p7ojDkLj//YCGv/FbAMeT+JNTgFcNXM9TvAh4KFFLEb6//8uSb/vsXf/IjdrWQfsMJ0VQNvZvoT98i8MG/qSJJuu8s5/KfckGTfFFEArSbIGuGeJxWxOsrGP7/5L5T4zTQG0lY011cPn+3Tsv2bpJTGWxFxXAK3kMzWVs64PYzNWUzlDfRofBdAH1JWY641NT2SiAKRW6tqKfDRJv+23P9zCOCsAsT7FhBERBSAiCkBEFICIKACRhbDKECgAUQCiAEREAYiIAhARBdAPuCKNKIABZsQQiAIQEQUgIgpARBSAiCgAEXAjUgUgAm5EqgDATUhEFAC4DZmIAhARBSAiCkBEFIAsA/OGQAHI4DJtCBSAiCgAEVEAIqIAREQBgLPzRBRAh3nfEIgCEBEFICIKQKTLXGsIFICAy7OLAhARBSCiAEREAQg4zhUFIOBGpKIAREQBiIgCEKmb1YZAAYiIAhARBSAiCkBEFAD4bl5EAYCz80QUgIgoABFRANLfnDUECkAGl1lDoABERAGIiAIQEQUgIgqgH5g3BKIABpdpQyAKQEQUgICTc0QBSCtJ4uQcBSAiCkBEFICIKAARUQB9wYwhEAUwuMwZAlEAIuDirApABFycVQGIiAIQEQUgIgpARBSAiCgAEVEAIqIApKcMGwJRAIPLyo6d7yqrTAHI4KIAFICIKAARUQAiogBERAH0BdcaAlEA4HfoIgpABNyFSAGIfBzuQqQAREQBiIgCEBEFICIKQEQUgIgoABFRACKiAEREAbSfdwd4hR2n+iqAgWemxrKua/pkk4zWWNy01a8AFEB9rOnY140zVr8CUAD1sTFJ0ysDT4A9AAUgbRTAEHBLw+d7U41lvWf1K4CBJsmZmov8cpIVDZ3rDcAGsAegAMC7cn2crrGsMWBbA41/BXB/zcVO9TDGQ6a1Algocz0+3uGay7svydqay7wHWF9jeWeTTPYwxi7NpgBay381sEfgo0lqSfokW4D7aj7HQ1a7AhAAmGxgUswa4LEka5bY+LcCuzogPVEA3STJPHCkgaLXAd9KsnkR5zSUZCewq4E8mQWOWvPLjw9G2sObwNYGyl0FPJzkBLAHOFoJ54oNH7jQ5W9q3HwkyZxVrgDkg2PimQYb3XrgG8DZJIeAt4EZ4BwwCqwG/hQY70FevGF1twOHAC2huiO+2INDDQN3AA8ADwOPAQ8B24GJHjT+E5WARAHIJeyteU5AG9ltNSsAuQzV2Pz5Pr7EQ0mOWdMKQK5AkoPAiT68tH6XmwKQ2ngOmO+za3otyZRVqwDkY0gyCezpo0ua6rPrUQDSLEleAvb1waWcBZ5M4vJfCkCukmeAyQ6f/1zV+O36KwBZ5FuBp4AzHb2Ef+vxF3+iAPqLJDPADzu4ft6eJAesQQUgSyTJKeDvO/J6cB7YneTH1pwCkJqm1lY9ge8B+1t8rTPAD5K8bLUPUHL2IXW+gx+p+XuBf03yW2BHywQ+CTxViaqN+PXhZbAH0MHFKqtXhE+25LuBeeAV4Pstbvy4BwHYA1gehhuSwOEkR4CtDX+7/1ENfz/wYpKmRLTa9FEAXb9brGz4NeG+6mn7ncD2HuwP2IuG30R+OgS4DNcagg9TluV8URRFXQIoiuKlsiz/t8nzLcvyeFEUPwfeA64BRmse4k0B+6p3+/vKsvx90/VQFMVEjRuRTJZl6TqEDRq235it6e69AlgLnGr6hJOcA149/0uyEhgHbgYmgNFFXP8x4M1qCa/lmIz06ZrrUxTAgjkNjNVUVk8EcDFJZoHD1Y8kq4GRS37XAQDngPeAGWAaOAPMtGDdvjo3O33HlFYAV9vlrUsAnwUOLufFVHfwrk0pHqtZ6HKZ7qk0f8eYMJxXR5KxRQxbPk7oogCWJWHWJxk1pFdFnbsczy3TMwwFoAAaSehB4Gaw+68Alo/TNU8JvtmQLoyqt1Tn+P+EUVUAi3mKXmfijCdZZWQXxKaay3vLkCqAxfCrmmO9yZAuiFtrLs+lyBVAKxLHYcDHUPWSbqixyCkfACqAxXK85jnktyx1u+4B4N6a56d491cAiyPJHHC85njvMLJXjPcIcDc4/lcA7eE/ay5vS5L1hvWy3Ffz3X8WcCNSBbAkDjTwKelOw/pBkqwFttZc7MEWfM+gADqemOcauIuMJ3F68Ifv/nXn4xuGVQHUwetNJHwS4w8kuaGBmZLTSY4aXQVQB0cbWCdwDLjDxp8h4P4Git5v2iqAupJ0HvhFA0Xv9IEgXwPqjkFT9aUABphXGlhVZiXwaPX6a+BIchewrYGi9/dgvUIFMIAPA19roOgR4JGqKzxI8RxvqOsP8BMzVgE0lVhNvFbaADw4YF/7fb2h/DvkbsQKoKnEnQH2NlT81iR/MQAxHAIeaXAfgxfMVAXQJD9tcI35HUm29nHjXwk83MBDv4sn/pw0RRVAk0k8DbzY4CF2JdnRh3EbAR5v8JPoOWC3GQpuDNIwRVH8GtgC/FFDh/h8URTri6I4XJbl+33Q+MeAx2te5vtSXkjixh9gD6AHCT0HPNvwYTYBf12t59/lWG0CHm9478LTgFuSgz2AXlGW5emiKMaAtQ0e5o+BLed7HGVZvtvR9/wP9WD/iX/xyf/iuMYQLPl11reb2gH4kpltLwIvJ5ntQFzWATt7tB/Ca0meMRsVwHIl+wTwjR4dbgbYU+0IPN/CWIwAO4BtPRpeTgHf7YIUFUAfk+Q+4Cs9POQpYHeSIy25/qHq+r/c5HbolzAHPOFrPwWw7CRZAXyz5sUsF/qV4nNJTi3Tda8EtgA7Gn7IdznOX/crZp8CaNPzgG8tQ0MAOAn8Ejjc9B2xavS3ALcCE8u0weyBJE+bdQqgVVSf9n6zh93gK70SOwz8Ejhex7OCljT6C0wCP3CpLwXQ5q/cHmvJHIuzwCQwBbxd/T1bzWa8UkMfBkaA9cD1wFj13234WvFUNe73oZ8CaC9JNjf4tVtdzF+yytFoy8/3NPC96oMsAScCtZWyLH9bFMVsj96DL0X+qy76tflmMAP8ozv8KIAuSeB4URTvATfZ01oSU9WY/x1DoQC6JoHfFEUxBdyqBBY95v+HJO8aCgXQ5eHAMeA2431VTALfT/J7Q6EAui6BdysJTACfNCIL2o3pn33a3zx2S3tIkmFgV8sfDi73m4nnkrxqKBRA35LkK8AO12P4AGeAp5KcMBTgEKCPKctyshoS3OiQAIBDwD8l+Z2hAHsAA0KSlcB24O4B7Q1MV13+g2aDAhhYkqwDHgQ2DNBYfy+wp9pwRRSAJLkT2L5MXxT2ihPAs0mOW+MKQC6/uMadwL19JoKTwAtJDlnLCkAGRwQ2fAUgSxTBVuD2Dj0jmAMOAa+3ZdkyUQCdJ8la4EvAtpb2Co4Dr1cr9vhwTwFIg2sQjgM3AhPAumW80x8D/hs45J78CkCWbznujcAXgPXA2obmFZwDTgGTwK+A4y7NpQCknT2EtdVvHfAZYBhYecnfy03HvfB3HjgNvANMASddjaf/+L8BAAw2SjFnEJ1ZAAAAAElFTkSuQmCC"
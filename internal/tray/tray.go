@@ -6,20 +6,34 @@ package tray
 import (
 	"encoding/base64"
 	"fmt"
-	"log"
+	"os"
+	"strconv"
+	"time"
 
 	"fyne.io/systray"
 	"github.com/gen2brain/beeep"
+	"github.com/yoshapihoff/smart-clipboard/internal/autostart"
 	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
 	"github.com/yoshapihoff/smart-clipboard/internal/config"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+	"github.com/yoshapihoff/smart-clipboard/internal/prompt"
 	"github.com/yoshapihoff/smart-clipboard/internal/storage"
 	"github.com/yoshapihoff/smart-clipboard/internal/types"
 )
 
+// Available сообщает, есть ли в этой сборке значок в трее
+const Available = true
+
+var logger = logging.For("tray")
+
 var trayIcon []byte
+var trayPausedIcon []byte
 var menuItemPool *GenericSlice[*systray.MenuItem]
 var menuCancelChannels []chan struct{}
 
+// configUpdates передаёт в обработчик меню перечитанную конфигурацию
+var configUpdates = make(chan *config.Config, 1)
+
 func initMenuItemPool(size int) {
 	stopMenuHandlers()
 	menuItemPool = NewGenericSliceWithCapacity[*systray.MenuItem](size)
@@ -32,18 +46,49 @@ func initMenuItemPool(size int) {
 	}
 }
 
-func RunTray(manager *clipboard.Manager, store *storage.Storage, cfg *config.Config) {
-	systray.Run(onReady(manager, store, cfg), onExit(store))
+// RunTray показывает значок в трее и возвращается после выхода из меню или Quit.
+// Историю при завершении сохраняет вызывающая сторона.
+func RunTray(manager *clipboard.Manager, store *storage.Storage, live *config.Live) {
+	systray.Run(onReady(manager, store, live), onExit(store))
+}
+
+// Quit закрывает трей, RunTray после этого возвращается
+func Quit() {
+	systray.Quit()
 }
 
-func onReady(manager *clipboard.Manager, store *storage.Storage, cfg *config.Config) func() {
+// ApplyConfig передаёт трею перечитанную конфигурацию: пункты настроек и
+// размер меню истории обновляются без перезапуска. Если трей ещё не
+// обработал предыдущую конфигурацию, она заменяется новой.
+func ApplyConfig(cfg *config.Config) {
+	select {
+	case <-configUpdates:
+	default:
+	}
+	configUpdates <- cfg
+}
+
+func onReady(manager *clipboard.Manager, store *storage.Storage, live *config.Live) func() {
 	return func() {
+		cfg := live.Load()
 		systray.SetIcon(getIcon())
 		tooltip := "Smart clipboard"
 		systray.SetTooltip(tooltip)
 
 		settingsMenu := systray.AddMenuItem("Settings", "Open settings")
 		clearMenu := systray.AddMenuItem("Clear history", "Clear all history")
+		pauseMenu := systray.AddMenuItem("Pause capture", "Temporarily stop saving clipboard contents")
+		pause5mMenu := pauseMenu.AddSubMenuItem("For 5 minutes", "Pause capture for 5 minutes")
+		pause1hMenu := pauseMenu.AddSubMenuItem("For 1 hour", "Pause capture for 1 hour")
+		pauseForeverMenu := pauseMenu.AddSubMenuItem("Until resumed", "Pause capture until resumed")
+		resumeMenu := systray.AddMenuItem("Resume capture", "Resume saving clipboard contents")
+		resumeMenu.Hide()
+		lockMenu := systray.AddMenuItem("Lock history", "Hide history until the passphrase is entered")
+		unlockMenu := systray.AddMenuItem("Unlock history…", "Enter the passphrase to show history")
+		unlockMenu.Hide()
+		if !store.SupportsLock() {
+			lockMenu.Hide()
+		}
 		systray.AddSeparator()
 		quitMenu := systray.AddMenuItem("Quit", "Quit program")
 
@@ -53,14 +98,29 @@ func onReady(manager *clipboard.Manager, store *storage.Storage, cfg *config.Con
 		decMaxItemsMenu := settingsMenu.AddSubMenuItem("-5 items", "Decrease max items")
 		settingsMenu.AddSeparator()
 		debugModeMenu := settingsMenu.AddSubMenuItem(fmt.Sprintf("Debug mode: %t", cfg.DebugMode), "Debug mode")
-
-		// Sync is always enabled
+		autostartMenu := settingsMenu.AddSubMenuItemCheckbox("Start at login", "Start smart clipboard when you log in", autostart.Enabled())
+		if !autostart.Supported() {
+			autostartMenu.Hide()
+		}
 
 		initMenuItemPool(cfg.MaxItems)
 
+		manager.SetPauseCallback(func(paused bool) {
+			updatePauseState(manager, pauseMenu, resumeMenu, paused)
+		})
+		updatePauseState(manager, pauseMenu, resumeMenu, manager.IsPaused())
+
+		manager.SetLockCallback(func(locked bool) {
+			updateLockState(lockMenu, unlockMenu, locked)
+		})
+		if store.SupportsLock() {
+			updateLockState(lockMenu, unlockMenu, manager.IsLocked())
+		}
+
 		go func() {
 			for range systray.TrayOpenedCh {
-				rebuildHistoryMenu(manager, store, cfg)
+				manager.Touch()
+				rebuildHistoryMenu(manager, store, live.Load())
 			}
 		}()
 
@@ -68,29 +128,55 @@ func onReady(manager *clipboard.Manager, store *storage.Storage, cfg *config.Con
 			for {
 				select {
 				case <-incMaxItemsMenu.ClickedCh:
-					cfg.MaxItems += 5
-					initMenuItemPool(cfg.MaxItems)
-					maxItemsMenu.SetTitle(fmt.Sprintf("Max items: %d", cfg.MaxItems))
-					config.SaveConfig(cfg)
+					if live.Load().MaxItems+5 <= config.MaxItemsLimit {
+						cfg := live.Update(func(cfg *config.Config) { cfg.MaxItems += 5 })
+						initMenuItemPool(cfg.MaxItems)
+						manager.SetMaxItems(cfg.MaxItems)
+						maxItemsMenu.SetTitle(fmt.Sprintf("Max items: %d", cfg.MaxItems))
+						saveKey("max_items", strconv.Itoa(cfg.MaxItems))
+					}
 				case <-decMaxItemsMenu.ClickedCh:
-					if cfg.MaxItems > 5 {
-						cfg.MaxItems -= 5
+					if live.Load().MaxItems > 5 {
+						cfg := live.Update(func(cfg *config.Config) { cfg.MaxItems -= 5 })
 						initMenuItemPool(cfg.MaxItems)
+						manager.SetMaxItems(cfg.MaxItems)
 						maxItemsMenu.SetTitle(fmt.Sprintf("Max items: %d", cfg.MaxItems))
+						saveKey("max_items", strconv.Itoa(cfg.MaxItems))
 					}
 				case <-debugModeMenu.ClickedCh:
-					cfg.DebugMode = !cfg.DebugMode
+					cfg := live.Update(func(cfg *config.Config) { cfg.DebugMode = !cfg.DebugMode })
+					logging.SetDebug(cfg.DebugMode)
+					debugModeMenu.SetTitle(fmt.Sprintf("Debug mode: %t", cfg.DebugMode))
+					saveKey("debug_mode", strconv.FormatBool(cfg.DebugMode))
+				case cfg := <-configUpdates:
+					// Изменения файла конфигурации, применённые демоном
+					if cfg.MaxItems != menuItemPool.Length() {
+						initMenuItemPool(cfg.MaxItems)
+					}
+					maxItemsMenu.SetTitle(fmt.Sprintf("Max items: %d", cfg.MaxItems))
 					debugModeMenu.SetTitle(fmt.Sprintf("Debug mode: %t", cfg.DebugMode))
-					config.SaveConfig(cfg)
+				case <-autostartMenu.ClickedCh:
+					toggleAutostart(autostartMenu)
+				case <-pause5mMenu.ClickedCh:
+					manager.Pause(5 * time.Minute)
+				case <-pause1hMenu.ClickedCh:
+					manager.Pause(time.Hour)
+				case <-pauseForeverMenu.ClickedCh:
+					manager.Pause(0)
+				case <-resumeMenu.ClickedCh:
+					manager.Resume()
+				case <-lockMenu.ClickedCh:
+					manager.Lock()
+				case <-unlockMenu.ClickedCh:
+					go unlockHistory(manager, store)
 				case <-clearMenu.ClickedCh:
 					manager.ClearClipboard()
 					manager.ClearHistory()
 					store.SaveHistory(manager.GetHistory())
-					initMenuItemPool(cfg.MaxItems)
+					initMenuItemPool(live.Load().MaxItems)
 					beeep.Notify("Smart clipboard", "History cleared", "")
 				case <-quitMenu.ClickedCh:
 					stopMenuHandlers()
-					store.SaveHistory(manager.GetHistory())
 					systray.Quit()
 					return
 				}
@@ -99,16 +185,52 @@ func onReady(manager *clipboard.Manager, store *storage.Storage, cfg *config.Con
 	}
 }
 
+// toggleAutostart включает или выключает запуск при входе в систему
+func toggleAutostart(menuItem *systray.MenuItem) {
+	var err error
+	if autostart.Enabled() {
+		err = autostart.Disable()
+	} else {
+		var exe string
+		exe, err = os.Executable()
+		if err == nil {
+			err = autostart.Enable(exe, Icon())
+		}
+	}
+	if err != nil {
+		logger.Error("failed to change autostart", "err", err)
+		beeep.Notify("Smart clipboard", "Failed to change start at login: "+err.Error(), "")
+	}
+
+	if autostart.Enabled() {
+		menuItem.Check()
+	} else {
+		menuItem.Uncheck()
+	}
+}
+
+// saveKey сохраняет настройку, изменённую в меню, в файл конфигурации.
+// Остальные значения работающей конфигурации, в том числе из окружения
+// и флагов запуска, в файл не записываются.
+func saveKey(key, value string) {
+	if err := config.SaveKey(key, value); err != nil {
+		logger.Error("failed to save config", "key", key, "err", err)
+	}
+}
+
 func onExit(store *storage.Storage) func() {
 	return func() {
 		stopMenuHandlers()
 	}
 }
 
+// stopMenuHandlers останавливает обработчики пунктов истории. Повторный
+// вызов, например из onExit после пункта Quit, ничего не делает.
 func stopMenuHandlers() {
-	for _, cancelChan := range menuCancelChannels {
+	for i, cancelChan := range menuCancelChannels {
 		if cancelChan != nil {
 			close(cancelChan)
+			menuCancelChannels[i] = nil
 		}
 	}
 }
@@ -123,6 +245,20 @@ func rebuildHistoryMenu(manager *clipboard.Manager, store *storage.Storage, cfg
 		menuCancelChannels[i] = make(chan struct{})
 	}
 
+	if manager.IsLocked() {
+		for i := 0; i < menuItemPool.Length(); i++ {
+			menuItem, _ := menuItemPool.Get(i)
+			menuItem.Hide()
+		}
+		if menuItem, ok := menuItemPool.Get(0); ok {
+			menuItem.SetTitle("Locked")
+			menuItem.SetTooltip("Unlock history to see clipboard items")
+			menuItem.Disable()
+			menuItem.Show()
+		}
+		return
+	}
+
 	if len(history) == 0 {
 		if menuItemPool.Length() > 0 {
 			if menuItem, ok := menuItemPool.Get(0); ok {
@@ -159,8 +295,14 @@ func rebuildHistoryMenu(manager *clipboard.Manager, store *storage.Storage, cfg
 			for {
 				select {
 				case <-menuItem.ClickedCh:
-					manager.CopyToClipboard(clipboardItem.Content)
-					manager.IncrementClickCount(clipboardItem.Content)
+					manager.Touch()
+					content, err := manager.ItemContent(clipboardItem)
+					if err != nil {
+						logger.Error("failed to load item content", "err", err)
+						return
+					}
+					manager.CopyToClipboard(content)
+					manager.IncrementClickCount(clipboard.ByID(clipboardItem.ID()))
 					return
 				case <-cancelChan:
 					return
@@ -170,6 +312,72 @@ func rebuildHistoryMenu(manager *clipboard.Manager, store *storage.Storage, cfg
 	}
 }
 
+// updateLockState показывает пункт Lock или Unlock в зависимости от состояния
+func updateLockState(lockMenu, unlockMenu *systray.MenuItem, locked bool) {
+	if locked {
+		lockMenu.Hide()
+		unlockMenu.Show()
+	} else {
+		unlockMenu.Hide()
+		lockMenu.Show()
+	}
+}
+
+// unlockHistory запрашивает пароль и снимает блокировку истории, если он
+// подходит к ключу хранилища
+func unlockHistory(manager *clipboard.Manager, store *storage.Storage) {
+	passphrase, err := prompt.Passphrase("Smart-clipboard passphrase")
+	if err != nil {
+		if err != prompt.ErrCancelled {
+			logger.Error("failed to read passphrase", "err", err)
+		}
+		return
+	}
+
+	if err := store.CheckPassphrase(passphrase); err != nil {
+		beeep.Notify("Smart clipboard", "Wrong passphrase, history stays locked", "")
+		return
+	}
+	manager.Unlock()
+}
+
+// updatePauseState переключает значок, подсказку и пункты паузы и
+// возобновления в зависимости от того, приостановлен ли захват
+func updatePauseState(manager *clipboard.Manager, pauseMenu, resumeMenu *systray.MenuItem, paused bool) {
+	if !paused {
+		systray.SetIcon(getIcon())
+		systray.SetTooltip("Smart clipboard")
+		resumeMenu.Hide()
+		pauseMenu.Show()
+		return
+	}
+
+	systray.SetIcon(getPausedIcon())
+	if until := manager.PausedUntil(); !until.IsZero() {
+		systray.SetTooltip(fmt.Sprintf("Smart clipboard (paused until %s)", until.Format("15:04")))
+		resumeMenu.SetTitle(fmt.Sprintf("Resume capture (paused until %s)", until.Format("15:04")))
+	} else {
+		systray.SetTooltip("Smart clipboard (paused)")
+		resumeMenu.SetTitle("Resume capture")
+	}
+	pauseMenu.Hide()
+	resumeMenu.Show()
+}
+
+func getPausedIcon() []byte {
+	if len(trayPausedIcon) > 0 {
+		return trayPausedIcon
+	}
+
+	icon, err := base64.StdEncoding.DecodeString(iconPausedBase64)
+	if err != nil {
+		logger.Error("failed to decode paused icon", "err", err)
+		return getIcon()
+	}
+	trayPausedIcon = icon
+	return trayPausedIcon
+}
+
 func getIcon() []byte {
 	if len(trayIcon) > 0 {
 		return trayIcon
@@ -177,7 +385,7 @@ func getIcon() []byte {
 
 	trayIcon, err := base64.StdEncoding.DecodeString(iconBase64)
 	if err != nil {
-		log.Printf("tray: failed to decode base64 icon: %v", err)
+		logger.Error("failed to decode icon", "err", err)
 		return nil
 	}
 	return trayIcon
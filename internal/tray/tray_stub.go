@@ -3,24 +3,30 @@
 
 // The stub implementation of the tray package for environments where CGO is
 // disabled or a system tray is not available (for example, many minimal Linux
-// containers). It provides a no-op RunTray so the rest of the application can
-// still compile and run in headless mode.
+// containers). Available is false, so the daemon runs in headless mode and
+// never calls RunTray.
 package tray
 
 import (
-    "log"
-
     "github.com/yoshapihoff/smart-clipboard/internal/clipboard"
     "github.com/yoshapihoff/smart-clipboard/internal/config"
+    "github.com/yoshapihoff/smart-clipboard/internal/logging"
     "github.com/yoshapihoff/smart-clipboard/internal/storage"
 )
 
+var logger = logging.For("tray")
+
+// Available reports whether this build has the system tray UI
+const Available = false
+
 // RunTray is a noop when CGO is disabled. We simply log a message so the user
 // knows the system-tray UI is not available in the current build.
-func RunTray(manager *clipboard.Manager, store *storage.Storage, cfg *config.Config) {
-    log.Println("[smart-clipboard] CGO disabled – system tray UI is not available. Running in headless mode.")
-    // Persist any existing history to avoid data loss.
-    if err := store.SaveHistory(manager.GetHistory()); err != nil {
-        log.Printf("[smart-clipboard] failed to persist history: %v", err)
-    }
+func RunTray(manager *clipboard.Manager, store *storage.Storage, live *config.Live) {
+    logger.Info("CGO disabled, system tray UI is not available")
 }
+
+// Quit is a noop when CGO is disabled.
+func Quit() {}
+
+// ApplyConfig is a noop when CGO is disabled.
+func ApplyConfig(cfg *config.Config) {}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+)
+
+const helpLine = "Enter select  ^T pin  ^D delete  ^X transform  ^U clear  Esc quit"
+
+// size возвращает размер терминала, а если он неизвестен — 80x24
+func (p *picker) size() (int, int) {
+	width, height, err := term.GetSize(int(p.in.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// listHeight — список занимает верхнюю половину экрана под строкой запроса
+func (p *picker) listHeight() int {
+	_, height := p.size()
+	return max((height-3)/2, 1)
+}
+
+func (p *picker) render() {
+	width, height := p.size()
+	listHeight := p.listHeight()
+	previewHeight := max(height-3-listHeight, 0)
+
+	// Прокручиваем список так, чтобы курсор был виден
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+listHeight {
+		p.offset = p.cursor - listHeight + 1
+	}
+
+	w := p.out
+	w.WriteString("\x1b[H\x1b[2J")
+
+	// Строка запроса
+	fmt.Fprintf(w, "%s\r\n", fit(fmt.Sprintf("> %s  (%d/%d)", string(p.query), len(p.filtered), len(p.entries)), width))
+
+	for row := 0; row < listHeight; row++ {
+		i := p.offset + row
+		if i < len(p.filtered) {
+			line := fit(p.listLine(p.filtered[i]), width)
+			if i == p.cursor {
+				line = "\x1b[7m" + pad(line, width) + "\x1b[0m"
+			}
+			w.WriteString(line)
+		}
+		w.WriteString("\r\n")
+	}
+
+	w.WriteString("\x1b[2m" + strings.Repeat("─", width) + "\x1b[0m\r\n")
+	for i, line := range p.previewLines(width, previewHeight) {
+		if i > 0 {
+			w.WriteString("\r\n")
+		}
+		w.WriteString(line)
+	}
+
+	// Строка состояния внизу экрана
+	status := helpLine
+	if p.message != "" {
+		status = p.message
+	}
+	fmt.Fprintf(w, "\x1b[%d;1H\x1b[2m%s\x1b[0m", height, fit(status, width))
+
+	// Курсор — в конце строки запроса
+	fmt.Fprintf(w, "\x1b[1;%dH", min(3+len(p.query), width))
+	w.Flush()
+}
+
+func (p *picker) listLine(e *entry) string {
+	flags := " "
+	if e.item.Pinned {
+		flags = "*"
+	}
+	return fmt.Sprintf("%s %3d  %-6s %s", flags, e.item.Index, e.item.Kind, oneLine(e.item.Preview))
+}
+
+// previewLines показывает полное содержимое выделенного элемента или,
+// в режиме преобразования, список преобразований
+func (p *picker) previewLines(width, height int) []string {
+	var lines []string
+
+	if p.transforming {
+		lines = append(lines, "Transform the selected item (Esc to cancel):")
+		for i, t := range clipboard.Transforms() {
+			lines = append(lines, fmt.Sprintf("  %d  %s", i+1, t.Name))
+		}
+	} else if e := p.current(); e != nil {
+		switch {
+		case e.item.Sensitive:
+			lines = []string{"(sensitive item, content hidden)"}
+		case !e.loaded:
+			lines = []string{fmt.Sprintf("(%d bytes, content not loaded)", e.item.Size)}
+		default:
+			for _, line := range strings.Split(strings.ReplaceAll(e.content, "\t", "    "), "\n") {
+				lines = append(lines, wrap(strings.TrimRight(line, "\r"), width)...)
+			}
+		}
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return lines
+}
+
+// oneLine заменяет переводы строк и табуляции пробелами
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// fit обрезает строку до width символов, удаляя управляющие символы
+func fit(s string, width int) string {
+	runes := []rune(strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s))
+	if len(runes) > width {
+		runes = runes[:width]
+	}
+	return string(runes)
+}
+
+func pad(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// wrap разбивает строку на части не длиннее width символов
+func wrap(s string, width int) []string {
+	runes := []rune(fit(s, len(s)))
+	if len(runes) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}
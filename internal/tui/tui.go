@@ -0,0 +1,376 @@
+// Package tui — полноэкранный выбор элемента истории в терминале. Он заменяет
+// трей на машинах без графики и в тайловых оконных менеджерах: показывает
+// историю, фильтрует её по мере ввода и копирует или выводит выбранный
+// элемент. Работает через API демона (см. пакет ipc), поэтому подходит и для
+// запущенного демона, и для файла истории напрямую.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+)
+
+// maxFilterContent — элементы крупнее этого размера фильтруются только по превью
+const maxFilterContent = 64 * 1024
+
+// Caller вызывает методы API демона
+type Caller interface {
+	Call(method string, params, result any) error
+}
+
+// Options настраивает поведение выбора
+type Options struct {
+	// Print возвращает содержимое выбранного элемента вместо копирования
+	Print bool
+}
+
+// Result — итог работы пикера
+type Result struct {
+	Selected bool
+	Content  string // Заполняется при Options.Print
+}
+
+type entry struct {
+	item    ipc.Item
+	content string // Пусто для конфиденциальных и крупных элементов, пока не загружено
+	loaded  bool
+	score   int
+}
+
+type picker struct {
+	caller Caller
+	opts   Options
+
+	in  *os.File
+	out *bufio.Writer
+
+	entries  []*entry
+	filtered []*entry
+	query    []rune
+	cursor   int
+	offset   int
+
+	transforming bool
+	message      string
+}
+
+// Run показывает пикер на управляющем терминале и ждёт выбора
+func Run(caller Caller, opts Options) (Result, error) {
+	in, out, closeTTY, err := openTTY()
+	if err != nil {
+		return Result{}, err
+	}
+	defer closeTTY()
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return Result{}, fmt.Errorf("terminal is not interactive: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	p := &picker{caller: caller, opts: opts, in: in, out: bufio.NewWriter(out)}
+	if err := p.load(); err != nil {
+		return Result{}, err
+	}
+
+	// Альтернативный экран не портит прокрутку терминала
+	p.out.WriteString("\x1b[?1049h")
+	defer func() {
+		p.out.WriteString("\x1b[?1049l")
+		p.out.Flush()
+	}()
+
+	return p.loop()
+}
+
+// openTTY открывает управляющий терминал, чтобы стандартный вывод можно
+// было перенаправить в файл или конвейер
+func openTTY() (in, out *os.File, closeTTY func(), err error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err == nil {
+		return tty, tty, func() { tty.Close() }, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, nil, nil, fmt.Errorf("no terminal available")
+	}
+	return os.Stdin, os.Stderr, func() {}, nil
+}
+
+// load загружает историю в порядке менеджера и содержимое небольших
+// неконфиденциальных элементов для фильтрации и просмотра
+func (p *picker) load() error {
+	var items []ipc.Item
+	if err := p.caller.Call(ipc.MethodList, nil, &items); err != nil {
+		return err
+	}
+
+	p.entries = p.entries[:0]
+	for _, item := range items {
+		e := &entry{item: item}
+		if !item.Sensitive && item.Size <= maxFilterContent {
+			p.fetch(e)
+		}
+		p.entries = append(p.entries, e)
+	}
+	p.filter()
+	return nil
+}
+
+func (p *picker) fetch(e *entry) {
+	if e.loaded {
+		return
+	}
+	var full ipc.Item
+	if err := p.caller.Call(ipc.MethodGet, ipc.ItemRef{ID: e.item.ID}, &full); err != nil {
+		p.message = err.Error()
+		return
+	}
+	e.content = full.Content
+	e.loaded = true
+}
+
+// filter оставляет элементы, подходящие под запрос, лучшие совпадения выше.
+// При равной оценке сохраняется порядок менеджера.
+func (p *picker) filter() {
+	query := strings.ToLower(string(p.query))
+	p.filtered = p.filtered[:0]
+
+	for _, e := range p.entries {
+		if query == "" {
+			p.filtered = append(p.filtered, e)
+			continue
+		}
+		text := e.item.Preview
+		if e.loaded && !e.item.Sensitive {
+			text = e.content
+		}
+		text += " " + strings.Join(e.item.Tags, " ")
+		if e.score = fuzzyScore(strings.ToLower(text), query); e.score >= 0 {
+			p.filtered = append(p.filtered, e)
+		}
+	}
+
+	if query != "" {
+		sort.SliceStable(p.filtered, func(i, j int) bool {
+			return p.filtered[i].score > p.filtered[j].score
+		})
+	}
+	if p.cursor >= len(p.filtered) {
+		p.cursor = max(0, len(p.filtered)-1)
+	}
+}
+
+// fuzzyScore возвращает -1, если символы pattern не встречаются в text по порядку.
+// Иначе оценка тем выше, чем плотнее совпадение и чем чаще оно начинается с начала слова.
+func fuzzyScore(text, pattern string) int {
+	score := 0
+	prev := -2
+	pi := 0
+	runes := []rune(pattern)
+	textRunes := []rune(text)
+
+	for ti, r := range textRunes {
+		if pi == len(runes) {
+			break
+		}
+		if r != runes[pi] {
+			continue
+		}
+
+		score++
+		if ti == prev+1 {
+			score += 3 // Подряд идущие символы
+		}
+		if ti == 0 || !isWordRune(textRunes[ti-1]) {
+			score += 2 // Начало слова
+		}
+		prev = ti
+		pi++
+	}
+
+	if pi < len(runes) {
+		return -1
+	}
+	return score
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > utf8.RuneSelf
+}
+
+func (p *picker) current() *entry {
+	if len(p.filtered) == 0 {
+		return nil
+	}
+	return p.filtered[p.cursor]
+}
+
+func (p *picker) loop() (Result, error) {
+	buf := make([]byte, 64)
+
+	for {
+		p.render()
+
+		n, err := p.in.Read(buf)
+		if err != nil {
+			return Result{}, err
+		}
+
+		done, result, err := p.handleKey(buf[:n])
+		if err != nil || done {
+			return result, err
+		}
+	}
+}
+
+// handleKey обрабатывает одно нажатие. Escape-последовательность клавиши
+// терминал передаёт одним чтением.
+func (p *picker) handleKey(key []byte) (bool, Result, error) {
+	p.message = ""
+
+	if p.transforming {
+		p.transforming = false
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			p.transform(int(key[0] - '1'))
+		}
+		return false, Result{}, nil
+	}
+
+	switch string(key) {
+	case "\x1b", "\x03": // Esc, Ctrl-C
+		return true, Result{}, nil
+	case "\r", "\n":
+		return p.choose()
+	case "\x1b[A", "\x1bOA", "\x10": // Вверх, Ctrl-P
+		p.move(-1)
+	case "\x1b[B", "\x1bOB", "\x0e": // Вниз, Ctrl-N
+		p.move(1)
+	case "\x1b[5~":
+		p.move(-p.listHeight())
+	case "\x1b[6~":
+		p.move(p.listHeight())
+	case "\x7f", "\x08": // Backspace
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case "\x15": // Ctrl-U
+		p.query = p.query[:0]
+		p.filter()
+	case "\x14": // Ctrl-T
+		p.togglePin()
+	case "\x04": // Ctrl-D
+		p.delete()
+	case "\x18": // Ctrl-X
+		if p.current() != nil {
+			p.transforming = true
+		}
+	default:
+		if key[0] >= 0x20 && key[0] != 0x7f && utf8.Valid(key) {
+			p.query = append(p.query, []rune(string(key))...)
+			p.cursor = 0
+			p.offset = 0
+			p.filter()
+		}
+	}
+	return false, Result{}, nil
+}
+
+func (p *picker) move(delta int) {
+	p.cursor = min(max(p.cursor+delta, 0), max(len(p.filtered)-1, 0))
+}
+
+func (p *picker) choose() (bool, Result, error) {
+	e := p.current()
+	if e == nil {
+		return false, Result{}, nil
+	}
+
+	if p.opts.Print {
+		var full ipc.Item
+		if err := p.caller.Call(ipc.MethodGet, ipc.ItemRef{ID: e.item.ID}, &full); err != nil {
+			return true, Result{}, err
+		}
+		return true, Result{Selected: true, Content: full.Content}, nil
+	}
+
+	if err := p.caller.Call(ipc.MethodCopy, ipc.ItemRef{ID: e.item.ID}, nil); err != nil {
+		return true, Result{}, err
+	}
+	return true, Result{Selected: true}, nil
+}
+
+func (p *picker) togglePin() {
+	e := p.current()
+	if e == nil {
+		return
+	}
+	params := ipc.PinParams{ItemRef: ipc.ItemRef{ID: e.item.ID}, Pinned: !e.item.Pinned}
+	if err := p.caller.Call(ipc.MethodPin, params, nil); err != nil {
+		p.message = err.Error()
+		return
+	}
+	e.item.Pinned = !e.item.Pinned
+}
+
+func (p *picker) delete() {
+	e := p.current()
+	if e == nil {
+		return
+	}
+	if err := p.caller.Call(ipc.MethodDelete, ipc.ItemRef{ID: e.item.ID}, nil); err != nil {
+		p.message = err.Error()
+		return
+	}
+	p.reload("")
+}
+
+// transform применяет преобразование к выделенному элементу и добавляет
+// результат в историю как новый элемент
+func (p *picker) transform(i int) {
+	transforms := clipboard.Transforms()
+	e := p.current()
+	if e == nil || i >= len(transforms) {
+		return
+	}
+
+	p.fetch(e)
+	if !e.loaded {
+		return
+	}
+	result, err := transforms[i].Apply(e.content)
+	if err != nil {
+		p.message = fmt.Sprintf("%s: %v", transforms[i].Name, err)
+		return
+	}
+
+	var added ipc.Item
+	if err := p.caller.Call(ipc.MethodAdd, ipc.AddParams{Content: result}, &added); err != nil {
+		p.message = err.Error()
+		return
+	}
+	p.reload(added.ID)
+	p.message = transforms[i].Name + " applied, result added to history"
+}
+
+// reload перечитывает историю и ставит курсор на элемент id, если он задан
+func (p *picker) reload(id string) {
+	if err := p.load(); err != nil {
+		p.message = err.Error()
+		return
+	}
+	for i, e := range p.filtered {
+		if e.item.ID == id {
+			p.cursor = i
+		}
+	}
+}
@@ -1,12 +1,84 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 )
 
+// Виды содержимого элементов истории
+const (
+	KindText   = "text"
+	KindURL    = "url"
+	KindSecret = "secret"
+)
+
 type ClipboardItem struct {
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
-	Preview   string    `json:"preview"`
-	ClickCount int      `json:"click_count"`
+	Content    string    `json:"content"`
+	Timestamp  time.Time `json:"timestamp"`
+	Preview    string    `json:"preview"`
+	ClickCount int       `json:"click_count"`
+	Kind       string    `json:"kind,omitempty"`
+	Sensitive  bool      `json:"sensitive,omitempty"`
+	Pinned     bool      `json:"pinned,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+
+	// Крупное содержимое хранится в хранилище блобов: Content пуст,
+	// BlobHash — SHA-256 содержимого, Size — его размер в байтах
+	BlobHash string `json:"blob_hash,omitempty"`
+	Size     int    `json:"size,omitempty"`
+}
+
+// Retained сообщает, что элемент закреплён или помечен тегами
+// и не удаляется политиками хранения
+func (i ClipboardItem) Retained() bool {
+	return i.Pinned || len(i.Tags) > 0
+}
+
+// ContentSize возвращает размер содержимого в байтах
+func (i ClipboardItem) ContentSize() int {
+	if i.BlobHash != "" {
+		return i.Size
+	}
+	return len(i.Content)
+}
+
+// ID возвращает идентификатор элемента в истории: само содержимое
+// или, для элементов в хранилище блобов, хеш с префиксом "blob:"
+func (i ClipboardItem) ID() string {
+	if i.BlobHash != "" {
+		return "blob:" + i.BlobHash
+	}
+	return i.Content
+}
+
+// ContentHash возвращает SHA-256 содержимого в шестнадцатеричном виде —
+// адрес содержимого в хранилище блобов
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Операции изменения истории
+const (
+	OpAdd     = "add"
+	OpUse     = "use"
+	OpDelete  = "delete"
+	OpPin     = "pin"
+	OpClear   = "clear"
+	OpReplace = "replace"
+	OpTag     = "tag"
+	OpEdit    = "edit"
+)
+
+// Change описывает одно изменение истории. Используется для журнала
+// хранилища и для уведомления подписчиков о новых элементах.
+type Change struct {
+	Op         string          `json:"op"`
+	Item       *ClipboardItem  `json:"item,omitempty"`        // add, edit: новый элемент
+	Content    string          `json:"content,omitempty"`     // use, delete, pin, tag, edit: ID элемента
+	ClickCount int             `json:"click_count,omitempty"` // use
+	Pinned     bool            `json:"pinned,omitempty"`      // pin
+	Tags       []string        `json:"tags,omitempty"`        // tag
+	Items      []ClipboardItem `json:"items,omitempty"`       // replace
 }
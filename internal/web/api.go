@@ -0,0 +1,314 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+)
+
+// sseHeartbeat — интервал комментариев, которые не дают прокси и браузеру
+// закрыть простаивающий поток событий
+const sseHeartbeat = 30 * time.Second
+
+// maxBodySize ограничивает размер тела запроса
+const maxBodySize = 64 * 1024 * 1024
+
+// API версии v1. Все ответы — JSON, ошибки имеют вид
+// {"error": {"code": <HTTP-статус>, "message": "..."}}.
+//
+//	GET    /api/v1/status              состояние демона
+//	GET    /api/v1/items               список: q, kind, tag, pinned, since (RFC 3339), offset, limit
+//	POST   /api/v1/items               добавить: {"content": "...", "pinned": true, "tags": [...]}
+//	GET    /api/v1/items/{id}          элемент с полным содержимым
+//	PATCH  /api/v1/items/{id}          изменить: {"pinned": bool, "tags": [...], "content": "..."}
+//	DELETE /api/v1/items/{id}          удалить
+//	POST   /api/v1/items/{id}/copy     поместить в буфер обмена
+//	GET    /api/v1/events              изменения истории (server-sent events)
+func (s *Server) apiRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/status", s.handleStatus)
+	mux.HandleFunc("GET /api/v1/items", s.handleList)
+	mux.HandleFunc("POST /api/v1/items", s.handleCreate)
+	mux.HandleFunc("GET /api/v1/items/{id}", s.handleGet)
+	mux.HandleFunc("PATCH /api/v1/items/{id}", s.handlePatch)
+	mux.HandleFunc("DELETE /api/v1/items/{id}", s.handleDelete)
+	mux.HandleFunc("POST /api/v1/items/{id}/copy", s.handleCopy)
+	mux.HandleFunc("GET /api/v1/events", s.handleEvents)
+}
+
+// defaultPageSize — размер страницы списка, если limit не задан
+const defaultPageSize = 100
+
+// itemList — страница списка; Total — число элементов, подходящих под фильтры
+type itemList struct {
+	Items  []ipc.Item `json:"items"`
+	Total  int        `json:"total"`
+	Offset int        `json:"offset"`
+	Limit  int        `json:"limit"`
+}
+
+// newItem — тело запроса на добавление элемента
+type newItem struct {
+	Content string   `json:"content"`
+	Pinned  bool     `json:"pinned"`
+	Tags    []string `json:"tags"`
+}
+
+// itemPatch — изменяемые поля элемента; отсутствующие поля не меняются
+type itemPatch struct {
+	Pinned  *bool     `json:"pinned"`
+	Tags    *[]string `json:"tags"`
+	Content *string   `json:"content"`
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	var status ipc.Status
+	if err := s.api.Call(ipc.MethodStatus, nil, &status); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := ipc.ListParams{Query: query.Get("q"), Kind: query.Get("kind"), Tag: query.Get("tag")}
+
+	if value := query.Get("pinned"); value != "" {
+		pinned, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid pinned value")
+			return
+		}
+		params.Pinned = &pinned
+	}
+	if value := query.Get("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be an RFC 3339 time")
+			return
+		}
+		params.Since = since
+	}
+
+	offset, err := queryInt(query.Get("offset"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid offset")
+		return
+	}
+	limit, err := queryInt(query.Get("limit"), defaultPageSize)
+	if err != nil || limit == 0 {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	var items []ipc.Item
+	if err := s.api.Call(ipc.MethodList, params, &items); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	page := itemList{Items: []ipc.Item{}, Total: len(items), Offset: offset, Limit: limit}
+	if offset < len(items) {
+		page.Items = items[offset:min(offset+limit, len(items))]
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// queryInt разбирает неотрицательный числовой параметр запроса
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return n, nil
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var body newItem
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var item ipc.Item
+	if err := s.api.Call(ipc.MethodAdd, ipc.AddParams{Content: body.Content, Pinned: body.Pinned}, &item); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if len(body.Tags) > 0 {
+		if err := s.api.Call(ipc.MethodTag, ipc.TagParams{ItemRef: ipc.ItemRef{ID: item.ID}, Tags: body.Tags}, nil); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		if err := s.api.Call(ipc.MethodGet, ipc.ItemRef{ID: item.ID}, &item); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Location", "/api/v1/items/"+item.ID)
+	writeJSON(w, http.StatusCreated, item)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	var item ipc.Item
+	if err := s.api.Call(ipc.MethodGet, ipc.ItemRef{ID: r.PathValue("id")}, &item); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) handlePatch(w http.ResponseWriter, r *http.Request) {
+	var patch itemPatch
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ref := ipc.ItemRef{ID: r.PathValue("id")}
+	if patch.Pinned != nil {
+		if err := s.api.Call(ipc.MethodPin, ipc.PinParams{ItemRef: ref, Pinned: *patch.Pinned}, nil); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+	}
+	if patch.Tags != nil {
+		if err := s.api.Call(ipc.MethodTag, ipc.TagParams{ItemRef: ref, Tags: *patch.Tags}, nil); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+	}
+	// Содержимое меняется последним: после правки у элемента новый ID
+	if patch.Content != nil {
+		var item ipc.Item
+		if err := s.api.Call(ipc.MethodEdit, ipc.EditParams{ItemRef: ref, Content: *patch.Content}, &item); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		ref = ipc.ItemRef{ID: item.ID}
+	}
+
+	var item ipc.Item
+	if err := s.api.Call(ipc.MethodGet, ref, &item); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := s.api.Call(ipc.MethodDelete, ipc.ItemRef{ID: r.PathValue("id")}, nil); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCopy(w http.ResponseWriter, r *http.Request) {
+	var item ipc.Item
+	if err := s.api.Call(ipc.MethodCopy, ipc.ItemRef{ID: r.PathValue("id")}, &item); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+// handleEvents отдаёт изменения истории потоком server-sent events
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	var status ipc.Status
+	if err := s.api.Call(ipc.MethodStatus, nil, &status); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if status.Locked {
+		writeAPIError(w, &ipc.Error{Code: ipc.CodeLocked, Message: "history is locked"})
+		return
+	}
+
+	events, cancel := s.api.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Op, data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// apiError — тело ответа с ошибкой
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	var body apiError
+	body.Error.Code = status
+	body.Error.Message = message
+	writeJSON(w, status, body)
+}
+
+// writeAPIError переводит ошибку API демона в HTTP-статус
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	var rpcErr *ipc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case ipc.CodeLocked:
+			status = http.StatusLocked
+		case ipc.CodeNotFound:
+			status = http.StatusNotFound
+		case ipc.CodeInvalidParams, ipc.CodeInvalidRequest:
+			status = http.StatusBadRequest
+		}
+	}
+	writeError(w, status, err.Error())
+}
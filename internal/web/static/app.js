@@ -0,0 +1,237 @@
+"use strict";
+
+const state = { items: [], selected: null, item: null, editing: false };
+const $ = (id) => document.getElementById(id);
+
+async function api(method, path, body) {
+  const options = { method, headers: {} };
+  if (body !== undefined) {
+    options.headers["Content-Type"] = "application/json";
+    options.body = JSON.stringify(body);
+  }
+  const resp = await fetch("/api/v1" + path, options);
+  if (resp.status === 204) return null;
+  const data = await resp.json();
+  if (!resp.ok) throw new Error(data.error ? data.error.message : resp.statusText);
+  return data;
+}
+
+function showMessage(text) {
+  const box = $("message");
+  box.textContent = text;
+  box.hidden = false;
+  clearTimeout(showMessage.timer);
+  showMessage.timer = setTimeout(() => { box.hidden = true; }, 3000);
+}
+
+async function loadItems() {
+  const params = new URLSearchParams();
+  if ($("search").value) params.set("q", $("search").value);
+  if ($("kind").value) params.set("kind", $("kind").value);
+  params.set("limit", "1000");
+  try {
+    const list = await api("GET", "/items?" + params);
+    state.items = list.items;
+  } catch (err) {
+    state.items = [];
+    showMessage(err.message);
+  }
+  renderItems();
+}
+
+function renderItems() {
+  const list = $("items");
+  list.replaceChildren();
+  for (const item of state.items) {
+    const li = document.createElement("li");
+    li.dataset.id = item.id;
+    if (item.id === state.selected) li.classList.add("selected");
+
+    const kind = document.createElement("span");
+    kind.className = "kind";
+    kind.textContent = item.kind || "text";
+    li.append(kind);
+
+    const preview = document.createElement("span");
+    if (item.pinned) preview.className = "pinned";
+    preview.textContent = item.preview.replace(/\s+/g, " ");
+    li.append(preview);
+
+    for (const tag of item.tags || []) {
+      const span = document.createElement("span");
+      span.className = "tag";
+      span.textContent = tag;
+      li.append(span);
+    }
+
+    li.addEventListener("click", () => selectItem(item.id));
+    list.append(li);
+  }
+}
+
+async function selectItem(id) {
+  state.selected = id;
+  state.editing = false;
+  renderItems();
+  try {
+    state.item = await api("GET", "/items/" + id);
+  } catch (err) {
+    state.item = null;
+    showMessage(err.message);
+  }
+  renderDetail();
+}
+
+function renderDetail() {
+  const item = state.item;
+  $("detail").hidden = !item;
+  if (!item) return;
+
+  $("pin").textContent = item.pinned ? "Unpin" : "Pin";
+  $("meta").textContent = `${item.kind || "text"} · ${item.size} bytes · copied ${item.click_count} times · ${new Date(item.timestamp).toLocaleString()}`;
+  $("tags").value = (item.tags || []).join(", ");
+
+  $("content").hidden = state.editing;
+  $("editor").hidden = !state.editing;
+  renderContent($("content"), item.content || "");
+  renderThumbnail(item.content || "");
+}
+
+function renderThumbnail(text) {
+  const box = $("thumbnail");
+  box.replaceChildren();
+  const value = text.trim();
+  if (/^data:image\/(png|jpeg|gif|webp);base64,[A-Za-z0-9+/=]+$/.test(value) ||
+      /^https?:\/\/\S+\.(png|jpe?g|gif|webp|svg)(\?\S*)?$/i.test(value)) {
+    const img = document.createElement("img");
+    img.src = value;
+    img.alt = "";
+    box.append(img);
+  }
+}
+
+// Простая подсветка синтаксиса: строки, числа, комментарии и ключевые слова
+const keywords = new Set(("break case catch class const continue def default defer do else elif enum export " +
+  "extends false fn for from func function go if import in interface let match new nil null return " +
+  "select self static struct switch this throw true try type undefined var void while yield").split(" "));
+const tokenPattern = /(\/\/[^\n]*|#[^\n]*|\/\*[\s\S]*?\*\/)|("(?:\\.|[^"\\\n])*"|'(?:\\.|[^'\\\n])*'|`[^`]*`)|\b(\d+(?:\.\d+)?)\b|\b([A-Za-z_]\w*)\b/g;
+
+function looksLikeCode(text) {
+  const trimmed = text.trim();
+  if (/^[\[{]/.test(trimmed)) {
+    try { JSON.parse(trimmed); return true; } catch (e) { /* не JSON */ }
+  }
+  const lines = trimmed.split("\n");
+  const codeLines = lines.filter((line) => /[{};]\s*$|^\s*(def|func|function|class|import|package|#include)\b/.test(line));
+  return lines.length > 1 && codeLines.length / lines.length >= 0.3;
+}
+
+function renderContent(pre, text) {
+  pre.replaceChildren();
+  if (!looksLikeCode(text)) {
+    pre.textContent = text;
+    return;
+  }
+
+  let last = 0;
+  for (const match of text.matchAll(tokenPattern)) {
+    let cls = null;
+    if (match[1]) cls = "tok-com";
+    else if (match[2]) cls = "tok-str";
+    else if (match[3]) cls = "tok-num";
+    else if (match[4] && keywords.has(match[4])) cls = "tok-kw";
+    if (!cls) continue;
+
+    pre.append(text.slice(last, match.index));
+    const span = document.createElement("span");
+    span.className = cls;
+    span.textContent = match[0];
+    pre.append(span);
+    last = match.index + match[0].length;
+  }
+  pre.append(text.slice(last));
+}
+
+async function updateItem(patch) {
+  try {
+    state.item = await api("PATCH", "/items/" + state.selected, patch);
+    state.selected = state.item.id;
+    state.editing = false;
+    renderDetail();
+    await loadItems();
+  } catch (err) {
+    showMessage(err.message);
+  }
+}
+
+function setupActions() {
+  $("copy").addEventListener("click", async () => {
+    try {
+      await api("POST", "/items/" + state.selected + "/copy");
+      showMessage("Copied");
+    } catch (err) {
+      showMessage(err.message);
+    }
+  });
+  $("pin").addEventListener("click", () => updateItem({ pinned: !state.item.pinned }));
+  $("tags").addEventListener("change", () => {
+    updateItem({ tags: $("tags").value.split(",").map((t) => t.trim()).filter(Boolean) });
+  });
+  $("edit").addEventListener("click", () => {
+    state.editing = true;
+    $("editText").value = state.item.content || "";
+    renderDetail();
+    $("editText").focus();
+  });
+  $("save").addEventListener("click", () => updateItem({ content: $("editText").value }));
+  $("cancel").addEventListener("click", () => {
+    state.editing = false;
+    renderDetail();
+  });
+  $("delete").addEventListener("click", async () => {
+    if (!confirm("Delete this item?")) return;
+    try {
+      await api("DELETE", "/items/" + state.selected);
+      state.selected = null;
+      state.item = null;
+      renderDetail();
+      await loadItems();
+    } catch (err) {
+      showMessage(err.message);
+    }
+  });
+
+  let searchTimer;
+  $("search").addEventListener("input", () => {
+    clearTimeout(searchTimer);
+    searchTimer = setTimeout(loadItems, 150);
+  });
+  $("kind").addEventListener("change", loadItems);
+}
+
+// Живые обновления: после любого изменения перечитываем список
+function subscribe() {
+  const source = new EventSource("/api/v1/events");
+  let reloadTimer;
+
+  source.onopen = () => $("live").classList.add("on");
+  source.onerror = () => $("live").classList.remove("on");
+  for (const op of ["add", "use", "delete", "pin", "tag", "edit", "clear", "replace"]) {
+    source.addEventListener(op, (e) => {
+      const event = JSON.parse(e.data);
+      if (event.op === "edit" && event.previous === state.selected && !state.editing) {
+        selectItem(event.id);
+      } else if ((event.op === "delete" && event.id === state.selected) || event.op === "clear") {
+        state.selected = null;
+        state.item = null;
+        renderDetail();
+      }
+      clearTimeout(reloadTimer);
+      reloadTimer = setTimeout(loadItems, 100);
+    });
+  }
+}
+
+setupActions();
+loadItems();
+subscribe();
@@ -0,0 +1,203 @@
+// Package web обслуживает локальный веб-интерфейс: встроенное одностраничное
+// приложение и версионированный HTTP/JSON API (/api/v1), которым пользуются
+// и сторонние программы. Сервер слушает только обратную петлю, и каждый
+// запрос должен нести токен доступа — в заголовке Bearer или в cookie,
+// которая ставится при открытии интерфейса по ссылке с токеном.
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+	"github.com/yoshapihoff/smart-clipboard/internal/logging"
+)
+
+var logger = logging.For("web")
+
+const tokenCookie = "smart_clipboard_token"
+
+//go:embed static
+var staticFiles embed.FS
+
+// Server — HTTP-сервер веб-интерфейса
+type Server struct {
+	api    *ipc.Server
+	listen string
+	token  string
+	http   *http.Server
+
+	// done закрывается при остановке сервера: Shutdown ждёт завершения
+	// обработчиков, а поток событий сам не заканчивается
+	done chan struct{}
+}
+
+// NewServer создаёт сервер поверх API демона. listen должен быть адресом
+// обратной петли.
+func NewServer(api *ipc.Server, listen, token string) (*Server, error) {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %q: %w", listen, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("web UI must listen on a loopback address, not %q", listen)
+	}
+
+	s := &Server{api: api, listen: listen, token: token, done: make(chan struct{})}
+	s.http = &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.http.RegisterOnShutdown(func() { close(s.done) })
+	return s, nil
+}
+
+// Start начинает принимать соединения в фоне
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.listen)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server stopped", "err", err)
+		}
+	}()
+	return nil
+}
+
+// Stop закрывает сервер, дожидаясь завершения запросов не дольше ctx
+func (s *Server) Stop(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
+
+// URL возвращает ссылку на интерфейс с токеном доступа
+func URL(listen, token string) string {
+	return fmt.Sprintf("http://%s/?token=%s", listen, url.QueryEscape(token))
+}
+
+// LoadToken читает токен доступа из файла, а если файла нет — создаёт новый
+func LoadToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	static, _ := fs.Sub(staticFiles, "static")
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServer(http.FS(static))))
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+
+	s.apiRoutes(mux)
+
+	return s.guard(mux)
+}
+
+// guard проверяет токен и защищает от DNS rebinding и запросов с чужих страниц
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+			http.Error(w, "forbidden origin", http.StatusForbidden)
+			return
+		}
+
+		// Ссылка с токеном ставит cookie и убирает токен из адресной строки
+		if token := r.URL.Query().Get("token"); token != "" && r.URL.Path == "/" {
+			if !s.validToken(token) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return s.validToken(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if cookie, err := r.Cookie(tokenCookie); err == nil {
+		return s.validToken(cookie.Value)
+	}
+	return false
+}
+
+func (s *Server) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func isLoopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	data, err := staticFiles.ReadFile("static/index.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; img-src 'self' data: https: http:; style-src 'self'")
+	w.Write(data)
+}
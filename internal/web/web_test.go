@@ -0,0 +1,233 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yoshapihoff/smart-clipboard/internal/clipboard"
+	"github.com/yoshapihoff/smart-clipboard/internal/ipc"
+)
+
+const testToken = "secret-token"
+
+// newTestServer создаёт веб-сервер над пустой историей
+func newTestServer(t *testing.T) (*Server, *clipboard.Manager) {
+	t.Helper()
+	manager := clipboard.NewManager(nil, 10, nil)
+	api := ipc.NewServer(manager, nil)
+	manager.SetChangeCallback(api.Publish)
+
+	s, err := NewServer(api, "127.0.0.1:0", testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, manager
+}
+
+// serve выполняет запрос к обработчику сервера в обход сети
+func serve(s *Server, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.http.Handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestNewServerRequiresLoopback(t *testing.T) {
+	api := ipc.NewServer(clipboard.NewManager(nil, 10, nil), nil)
+	for _, listen := range []string{"127.0.0.1:7390", "[::1]:7390", "localhost:7390"} {
+		if _, err := NewServer(api, listen, testToken); err != nil {
+			t.Errorf("NewServer(%q): %v", listen, err)
+		}
+	}
+	for _, listen := range []string{"0.0.0.0:7390", "192.168.1.5:7390", "example.com:7390", "7390"} {
+		if _, err := NewServer(api, listen, testToken); err == nil {
+			t.Errorf("NewServer(%q) accepted a non-loopback address", listen)
+		}
+	}
+}
+
+func TestIsLoopbackHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"127.0.0.1:7390", true},
+		{"127.0.0.1", true},
+		{"localhost:7390", true},
+		{"localhost", true},
+		{"[::1]:7390", true},
+		{"[::1]", true},
+		{"example.com:7390", false},
+		{"127.0.0.1.example.com", false},
+		{"192.168.1.5:7390", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopbackHost(tt.host); got != tt.want {
+			t.Errorf("isLoopbackHost(%q) = %t, want %t", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestValidToken(t *testing.T) {
+	s, _ := newTestServer(t)
+	for token, want := range map[string]bool{
+		testToken:                  true,
+		"":                         false,
+		"secret":                   false,
+		testToken + "x":            false,
+		strings.ToUpper(testToken): false,
+	} {
+		if got := s.validToken(token); got != want {
+			t.Errorf("validToken(%q) = %t, want %t", token, got, want)
+		}
+	}
+}
+
+func TestGuard(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		auth   string
+		cookie string
+		want   int
+	}{
+		{name: "bearer token", auth: "Bearer " + testToken, want: http.StatusOK},
+		{name: "cookie", cookie: testToken, want: http.StatusOK},
+		{name: "missing token", want: http.StatusUnauthorized},
+		{name: "wrong bearer token", auth: "Bearer wrong", want: http.StatusUnauthorized},
+		{name: "wrong cookie", cookie: "wrong", want: http.StatusUnauthorized},
+		{name: "token without Bearer", auth: testToken, want: http.StatusUnauthorized},
+		{name: "foreign host", host: "evil.example.com:7390", auth: "Bearer " + testToken, want: http.StatusForbidden},
+		{name: "foreign origin", origin: "http://evil.example.com", auth: "Bearer " + testToken, want: http.StatusForbidden},
+		{name: "own origin", origin: "http://127.0.0.1:7390", auth: "Bearer " + testToken, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+			r.Host = "127.0.0.1:7390"
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: tokenCookie, Value: tt.cookie})
+			}
+
+			if w := serve(s, r); w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestTokenLinkSetsCookie(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/?token="+testToken, nil)
+	r.Host = "127.0.0.1:7390"
+	w := serve(s, r)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+		t.Fatalf("status = %d, location = %q; want a redirect to /", w.Code, w.Header().Get("Location"))
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("cookies = %v, want the token cookie", cookies)
+	}
+	cookie := cookies[0]
+	if cookie.Name != tokenCookie || cookie.Value != testToken || !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode {
+		t.Fatalf("cookie = %+v, want an HttpOnly SameSite=Strict token cookie", cookie)
+	}
+
+	// Интерфейс открывается по полученной cookie
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Host = "127.0.0.1:7390"
+	r.AddCookie(cookie)
+	if w := serve(s, r); w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("index: status = %d, content type = %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestTokenLinkWithWrongToken(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	for _, target := range []string{"/?token=wrong", "/api/v1/status?token=" + testToken} {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Host = "127.0.0.1:7390"
+		w := serve(s, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: status = %d, want %d", target, w.Code, http.StatusUnauthorized)
+		}
+		if cookies := w.Result().Cookies(); len(cookies) != 0 {
+			t.Fatalf("%s: cookies = %v, want none", target, cookies)
+		}
+	}
+}
+
+// TestEventsStopOnShutdown проверяет, что поток событий доставляет изменения
+// и завершается при остановке сервера, не задерживая Shutdown
+func TestEventsStopOnShutdown(t *testing.T) {
+	s, manager := newTestServer(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.http.Serve(listener)
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+listener.Addr().String()+"/api/v1/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	if _, err := manager.Add("hello"); err != nil {
+		t.Fatal(err)
+	}
+	lines := bufio.NewScanner(resp.Body)
+	if !lines.Scan() || lines.Text() != "event: add" {
+		t.Fatalf("first line = %q, want the add event", lines.Text())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	// Остаток потока дочитывается до конца, а не до таймаута
+	done := make(chan struct{})
+	go func() {
+		for lines.Scan() {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event stream was not closed on shutdown")
+	}
+}